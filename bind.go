@@ -0,0 +1,269 @@
+package goinject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RegisterAs registers a singleton instance and binds the interface I to it.
+// The instance stays registered under its concrete type, and every interface bound
+// to it resolves to that same instance.
+//
+// Example:
+//
+//	store := &FileStore{}
+//	goinject.RegisterAs[Reader](container, store)
+//	goinject.RegisterAs[Writer](container, store)
+func RegisterAs[I any](c *Container, service any) error {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	iface := reflect.TypeFor[I]()
+	{
+		if err := c.implements(typeof, iface); err != nil {
+			return err
+		}
+	}
+
+	if err := c.addProvider(typeof, service); err != nil {
+		return err
+	}
+
+	return c.addBinding(iface, typeof)
+}
+
+// implements checks that iface is an interface implemented by typeof.
+func (c *Container) implements(typeof, iface reflect.Type) error {
+
+	if iface.Kind() != reflect.Interface {
+		return fmt.Errorf("%s %w", c.name(iface), ErrNotAnInterface)
+	}
+
+	if !typeof.Implements(iface) {
+		return fmt.Errorf("%s %w %s", c.name(typeof), ErrDoesNotImplement, c.name(iface))
+	}
+
+	return nil
+}
+
+// Bind registers ctor as the factory for *T and binds the interface I to it.
+// It returns an error if *T does not implement I.
+//
+// Example:
+//
+//	goinject.Bind[UserRepository](container, NewPostgresUserRepository)
+//	repo, _ := goinject.Get[UserRepository](container)
+func Bind[I, T any](c *Container, ctor func() *T) error {
+
+	typeof := reflect.TypeFor[*T]()
+
+	iface := reflect.TypeFor[I]()
+	{
+		if err := c.implements(typeof, iface); err != nil {
+			return err
+		}
+	}
+
+	if err := c.addFactory(typeof, newFactory(typeof, func(context.Context, *Container) (any, error) {
+		return ctor(), nil
+	})); err != nil {
+		return err
+	}
+
+	return c.addBinding(iface, typeof)
+}
+
+// BindMulti registers ctor as the factory for *T and binds each of the interfaces to it, so the
+// concrete type and every interface resolve to the same singleton. Interfaces are given as nil
+// pointers to them, like Get takes them. It returns an error, registering nothing, if one of them
+// is not an interface implemented by *T.
+//
+// Example:
+//
+//	goinject.BindMulti(container, NewFileStore, (*Reader)(nil), (*Writer)(nil))
+func BindMulti[T any](c *Container, ctor func() *T, ifaces ...any) error {
+
+	typeof := reflect.TypeFor[*T]()
+
+	types := make([]reflect.Type, len(ifaces))
+
+	for i, iface := range ifaces {
+		t, err := keyOf(iface)
+		{
+			if err != nil {
+				return fmt.Errorf("interface %d: %w", i, err)
+			}
+		}
+
+		if err := c.implements(typeof, t); err != nil {
+			return err
+		}
+
+		types[i] = t
+	}
+
+	if err := c.addFactory(typeof, newFactory(typeof, func(context.Context, *Container) (any, error) {
+		return ctor(), nil
+	})); err != nil {
+		return err
+	}
+
+	for _, iface := range types {
+		if err := c.addBinding(iface, typeof); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterFactoryAs registers a factory keyed under the interface I instead of its concrete type.
+// The factory's return type must implement I; the concrete type itself is not registered.
+//
+// Example:
+//
+//	goinject.RegisterFactoryAs[UserRepository](container, func() *postgresUserRepository {
+//	    return &postgresUserRepository{}
+//	})
+//	repo, _ := goinject.Get[UserRepository](container)
+func RegisterFactoryAs[I any](c *Container, factory any) error {
+
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	iface := reflect.TypeFor[I]()
+	{
+		if err := c.implements(typeof, iface); err != nil {
+			return err
+		}
+	}
+
+	return c.addFactory(iface, f)
+}
+
+// ProvideAs registers a constructor, like Provide, keyed under the interface I instead of
+// its concrete type. The constructor's arguments are resolved from the container and its
+// return type must implement I; the concrete type itself is not registered.
+//
+// Example:
+//
+//	goinject.ProvideAs[UserRepository](container, func(db *DB) (*postgresUserRepository, error) {
+//	    return newPostgresUserRepository(db)
+//	})
+//	repo, _ := goinject.Get[UserRepository](container)
+func ProvideAs[I any](c *Container, ctor any) error {
+
+	typeof, f, err := c.parseConstructor(ctor)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	iface := reflect.TypeFor[I]()
+	{
+		if err := c.implements(typeof, iface); err != nil {
+			return err
+		}
+	}
+
+	return c.addFactory(iface, f)
+}
+
+// ConcreteType returns the concrete type behind the registration of the out pointer's type.
+// For an interface binding or an interface-keyed factory it reveals the bound implementation,
+// for a concrete registration it returns the registered type itself. It resolves nothing.
+//
+// Example:
+//
+//	var repo UserRepository
+//	if t, ok := container.ConcreteType(&repo); ok {
+//	    log.Printf("UserRepository is backed by %s", t)
+//	}
+func (c *Container) ConcreteType(out any) (reflect.Type, bool) {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Registrations under the type win over bindings, as they do in resolution.
+	for {
+		if service, ok := c.providers[typeof]; ok {
+			return reflect.TypeOf(service), true
+		}
+
+		if factory := c.factories[typeof]; factory != nil {
+			return factory.concrete, true
+		}
+
+		target := c.bindings[typeof]
+		if target == nil {
+			return nil, false
+		}
+
+		typeof = target
+	}
+}
+
+// RegisterFactoryForTypes registers one factory under several types.
+// The factory's return type must be assignable to each of them, and every listed
+// type resolves to the same shared instance.
+//
+// Example:
+//
+//	container.RegisterFactoryForTypes(NewFileStore,
+//	    reflect.TypeFor[Reader](),
+//	    reflect.TypeFor[Writer](),
+//	)
+func (c *Container) RegisterFactoryForTypes(factory any, types ...reflect.Type) error {
+
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range types {
+		if !typeof.AssignableTo(t) {
+			return fmt.Errorf("%s %w %s", c.name(typeof), ErrDoesNotImplement, c.name(t))
+		}
+	}
+
+	c.hooked(typeof, f)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	for _, t := range types {
+		if err := c.shadowed(t); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range types {
+		c.putFactory(t, f)
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package goinject
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type (
+	TestReader interface {
+		Read() string
+	}
+
+	TestWriter interface {
+		Write(string)
+	}
+
+	TestStore struct {
+		data string
+	}
+)
+
+func (s *TestStore) Read() string      { return s.data }
+func (s *TestStore) Write(data string) { s.data = data }
+
+func TestRegisterAs(t *testing.T) {
+	c := New()
+	store := &TestStore{}
+
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("RegisterAs[TestReader]() unexpected error = %v", err)
+	}
+
+	if err := RegisterAs[TestWriter](c, store); err != nil {
+		t.Fatalf("RegisterAs[TestWriter]() unexpected error = %v", err)
+	}
+
+	var reader TestReader
+	viaReader, err := c.Get(&reader)
+	if err != nil {
+		t.Fatalf("Get(TestReader) unexpected error = %v", err)
+	}
+
+	var writer TestWriter
+	viaWriter, err := c.Get(&writer)
+	if err != nil {
+		t.Fatalf("Get(TestWriter) unexpected error = %v", err)
+	}
+
+	concrete, err := Get[TestStore](c)
+	if err != nil {
+		t.Fatalf("Get[TestStore]() unexpected error = %v", err)
+	}
+
+	if viaReader != any(store) || viaWriter != any(store) || concrete != store {
+		t.Errorf("bindings resolved to %p, %p and %p, want %p", viaReader, viaWriter, concrete, store)
+	}
+
+	writer.Write("shared")
+	if got := reader.Read(); got != "shared" {
+		t.Errorf("Read() got = %v, want %v", got, "shared")
+	}
+}
+
+func TestRegisterAs_DoesNotImplement(t *testing.T) {
+	c := New()
+
+	if err := RegisterAs[io.Reader](c, &TestStore{}); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("RegisterAs[io.Reader]() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+
+	if err := RegisterAs[TestService](c, &TestStore{}); !errors.Is(err, ErrNotAnInterface) {
+		t.Errorf("RegisterAs[TestService]() error = %v, want %v", err, ErrNotAnInterface)
+	}
+}
+
+func TestRegisterFactoryAs(t *testing.T) {
+	c := New()
+
+	if err := RegisterFactoryAs[TestReader](c, func() *TestStore {
+		return &TestStore{data: "from factory"}
+	}); err != nil {
+		t.Fatalf("RegisterFactoryAs[TestReader]() unexpected error = %v", err)
+	}
+
+	reader, err := Get[TestReader](c)
+	if err != nil {
+		t.Fatalf("Get[TestReader]() unexpected error = %v", err)
+	}
+
+	if got := (*reader).Read(); got != "from factory" {
+		t.Errorf("Read() got = %v, want %v", got, "from factory")
+	}
+
+	if _, err := Get[TestStore](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[TestStore]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if err := RegisterFactoryAs[io.Reader](c, func() *TestStore {
+		return &TestStore{}
+	}); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("RegisterFactoryAs[io.Reader]() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+}
+
+func TestContainer_ConcreteType(t *testing.T) {
+	c := New()
+
+	if err := RegisterAs[TestReader](c, &TestStore{}); err != nil {
+		t.Fatalf("failed to bind interface: %v", err)
+	}
+
+	if err := RegisterFactoryAs[TestWriter](c, func() *TestStore { return &TestStore{} }); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.Register(&TestService{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	storeType := reflect.TypeOf(&TestStore{})
+
+	var reader TestReader
+	if got, ok := c.ConcreteType(&reader); !ok || got != storeType {
+		t.Errorf("ConcreteType(TestReader) got = %v, %v, want %v, true", got, ok, storeType)
+	}
+
+	var writer TestWriter
+	if got, ok := c.ConcreteType(&writer); !ok || got != storeType {
+		t.Errorf("ConcreteType(TestWriter) got = %v, %v, want %v, true", got, ok, storeType)
+	}
+
+	serviceType := reflect.TypeOf(&TestService{})
+	if got, ok := c.ConcreteType((*TestService)(nil)); !ok || got != serviceType {
+		t.Errorf("ConcreteType(*TestService) got = %v, %v, want %v, true", got, ok, serviceType)
+	}
+
+	if got, ok := c.ConcreteType((*AnotherService)(nil)); ok {
+		t.Errorf("ConcreteType(*AnotherService) got = %v, true, want false", got)
+	}
+}
+
+func TestContainer_RegisterFactoryForTypes(t *testing.T) {
+	c := New()
+	builds := 0
+
+	if err := c.RegisterFactoryForTypes(func() *TestStore {
+		builds++
+		return &TestStore{}
+	}, reflect.TypeFor[TestReader](), reflect.TypeFor[TestWriter]()); err != nil {
+		t.Fatalf("RegisterFactoryForTypes() unexpected error = %v", err)
+	}
+
+	var reader TestReader
+	if _, err := c.Get(&reader); err != nil {
+		t.Fatalf("Get(TestReader) unexpected error = %v", err)
+	}
+
+	var writer TestWriter
+	if _, err := c.Get(&writer); err != nil {
+		t.Fatalf("Get(TestWriter) unexpected error = %v", err)
+	}
+
+	if reader != writer.(TestReader) || builds != 1 {
+		t.Errorf("shared factory built %d instances, want 1 shared", builds)
+	}
+
+	if err := c.RegisterFactoryForTypes(func() *TestStore {
+		return &TestStore{}
+	}, reflect.TypeFor[io.Reader]()); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("RegisterFactoryForTypes(io.Reader) error = %v, want %v", err, ErrDoesNotImplement)
+	}
+}
+
+func TestBind(t *testing.T) {
+	c := New()
+	builds := 0
+
+	if err := Bind[TestReader](c, func() *TestStore {
+		builds++
+		return &TestStore{}
+	}); err != nil {
+		t.Fatalf("Bind() unexpected error = %v", err)
+	}
+
+	store, err := Get[TestStore](c)
+	if err != nil {
+		t.Fatalf("Get[TestStore]() unexpected error = %v", err)
+	}
+
+	reader, err := Get[TestReader](c)
+	if err != nil {
+		t.Fatalf("Get[TestReader]() unexpected error = %v", err)
+	}
+
+	if *reader != TestReader(store) || builds != 1 {
+		t.Errorf("Get[TestReader]() got = %v, want %v built once", *reader, store)
+	}
+
+	if err := Bind[io.Reader](c, func() *TestStore {
+		return &TestStore{}
+	}); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("Bind() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+}
+
+func TestBindMulti(t *testing.T) {
+	c := New()
+	builds := 0
+
+	if err := BindMulti(c, func() *TestStore {
+		builds++
+		return &TestStore{}
+	}, (*TestReader)(nil), (*TestWriter)(nil)); err != nil {
+		t.Fatalf("BindMulti() unexpected error = %v", err)
+	}
+
+	store, err := Get[TestStore](c)
+	if err != nil {
+		t.Fatalf("Get[TestStore]() unexpected error = %v", err)
+	}
+
+	reader, err := Get[TestReader](c)
+	if err != nil {
+		t.Fatalf("Get[TestReader]() unexpected error = %v", err)
+	}
+
+	writer, err := Get[TestWriter](c)
+	if err != nil {
+		t.Fatalf("Get[TestWriter]() unexpected error = %v", err)
+	}
+
+	if *reader != TestReader(store) || *writer != TestWriter(store) || builds != 1 {
+		t.Errorf("BindMulti() resolved %p, %p and %p, want %p built once", *reader, *writer, store, store)
+	}
+
+	other := New()
+
+	if err := BindMulti(other, func() *TestStore {
+		return &TestStore{}
+	}, (*TestReader)(nil), (*io.Reader)(nil)); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("BindMulti() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+
+	if _, err := Get[TestStore](other); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[TestStore]() error = %v, a failed BindMulti should register nothing", err)
+	}
+}
+
+func TestProvideAs(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "postgres"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := ProvideAs[TestReader](c, func(db *TestService) (*TestStore, error) {
+		return &TestStore{data: "rows from " + db.Name}, nil
+	}); err != nil {
+		t.Fatalf("ProvideAs[TestReader]() unexpected error = %v", err)
+	}
+
+	reader, err := Get[TestReader](c)
+	if err != nil {
+		t.Fatalf("Get[TestReader]() unexpected error = %v", err)
+	}
+
+	if got := (*reader).Read(); got != "rows from postgres" {
+		t.Errorf("Read() got = %v, want %v", got, "rows from postgres")
+	}
+
+	if again, _ := Get[TestReader](c); *again != *reader {
+		t.Errorf("Get[TestReader]() got = %p, want the shared %p", *again, *reader)
+	}
+
+	if _, err := Get[TestStore](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[TestStore]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if err := ProvideAs[io.Reader](c, func(*TestService) *TestStore {
+		return &TestStore{}
+	}); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("ProvideAs[io.Reader]() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+}
@@ -0,0 +1,74 @@
+package goinject
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder accumulates registrations and validates them all at once when building the container.
+type Builder struct {
+	opts  []Option
+	steps []func(*Container) error
+}
+
+// NewBuilder creates a builder for a container configured with the given options.
+//
+// Example:
+//
+//	container, err := goinject.NewBuilder().
+//	    Add(&Config{Env: "prod"}).
+//	    AddFactory(NewLogger).
+//	    Provide(NewUserRepository).
+//	    Build()
+func NewBuilder(opts ...Option) *Builder {
+	return &Builder{opts: opts}
+}
+
+// Add adds a singleton instance, registered like Register.
+func (b *Builder) Add(service any) *Builder {
+	return b.step(func(c *Container) error {
+		return c.Register(service)
+	})
+}
+
+// AddFactory adds a factory, registered like RegisterFactory.
+func (b *Builder) AddFactory(factory any) *Builder {
+	return b.step(func(c *Container) error {
+		return c.RegisterFactory(factory)
+	})
+}
+
+// Provide adds a constructor, registered like Provide.
+func (b *Builder) Provide(ctor any) *Builder {
+	return b.step(func(c *Container) error {
+		return c.Provide(ctor)
+	})
+}
+
+// Build creates the container and registers everything that was added, in order.
+// If any registration is invalid, every error is joined with the position of the
+// registration and no container is returned.
+func (b *Builder) Build() (*Container, error) {
+
+	c := New(b.opts...)
+
+	var errs []error
+
+	for i, step := range b.steps {
+		if err := step(c); err != nil {
+			errs = append(errs, fmt.Errorf("registration %d: %w", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return c, nil
+}
+
+// step appends a registration to the builder.
+func (b *Builder) step(step func(*Container) error) *Builder {
+	b.steps = append(b.steps, step)
+	return b
+}
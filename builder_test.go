@@ -0,0 +1,47 @@
+package goinject
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	c, err := NewBuilder().
+		Add(&TestService{Name: "test"}).
+		AddFactory(func() *AnotherService { return &AnotherService{ID: 1} }).
+		Provide(func(service *TestService) *TestRepository { return &TestRepository{Service: service} }).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error = %v", err)
+	}
+
+	repository, err := Get[TestRepository](c)
+	if err != nil || repository.Service.Name != "test" {
+		t.Errorf("Get[TestRepository]() got = %v, %v", repository, err)
+	}
+
+	if service, err := Get[AnotherService](c); err != nil || service.ID != 1 {
+		t.Errorf("Get[AnotherService]() got = %v, %v", service, err)
+	}
+}
+
+func TestBuilder_Build_Invalid(t *testing.T) {
+	c, err := NewBuilder().
+		Add(TestService{}).
+		AddFactory(func() *AnotherService { return &AnotherService{} }).
+		Provide("not a constructor").
+		Build()
+
+	if c != nil {
+		t.Errorf("Build() got = %v, want no container", c)
+	}
+
+	if !errors.Is(err, ErrOutputMustBeAPointer) || !errors.Is(err, ErrFactoryMustBeAFunction) {
+		t.Errorf("Build() error = %v, want %v and %v", err, ErrOutputMustBeAPointer, ErrFactoryMustBeAFunction)
+	}
+
+	if msg := err.Error(); !strings.Contains(msg, "registration 0") || !strings.Contains(msg, "registration 2") {
+		t.Errorf("Build() error = %v, want the positions of the invalid registrations", err)
+	}
+}
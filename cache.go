@@ -0,0 +1,103 @@
+package goinject
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+)
+
+// lru tracks the instances built by cached factories, most recently resolved first.
+type lru struct {
+	mu      sync.Mutex
+	order   list.List
+	entries map[*factory]*list.Element
+}
+
+// lruEntry is a cached factory tracked by the lru.
+type lruEntry struct {
+	typeof  typeof
+	factory *factory
+}
+
+// RegisterCached registers a factory whose instance is shared like a singleton
+// but may be evicted when the container is created with WithMaxCachedInstances.
+// An evicted instance is disposed if it implements Disposable and rebuilt on next access.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithMaxCachedInstances(64))
+//	container.RegisterCached(func() *ReportCache {
+//	    return NewReportCache()
+//	})
+func (c *Container) RegisterCached(factory any) error {
+
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	f.lifetime = Cached
+
+	return c.addFactory(typeof, f)
+}
+
+// touch marks the cached factory as most recently resolved and evicts the least recently
+// resolved instances beyond the limit. A factory not tracked anymore is only added back
+// when it has just been built, as its instance may have been evicted meanwhile.
+func (c *Container) touch(typeof typeof, f *factory, built bool) {
+
+	if c.maxCached <= 0 {
+		return
+	}
+
+	c.cache.mu.Lock()
+
+	if c.cache.entries == nil {
+		c.cache.entries = make(map[*factory]*list.Element)
+	}
+
+	if element, ok := c.cache.entries[f]; ok {
+		c.cache.order.MoveToFront(element)
+	} else if built {
+		c.cache.entries[f] = c.cache.order.PushFront(&lruEntry{typeof: typeof, factory: f})
+	}
+
+	var evicted []*lruEntry
+
+	for c.cache.order.Len() > c.maxCached {
+		entry := c.cache.order.Remove(c.cache.order.Back()).(*lruEntry)
+		delete(c.cache.entries, entry.factory)
+		evicted = append(evicted, entry)
+	}
+
+	c.cache.mu.Unlock()
+
+	for _, entry := range evicted {
+		c.evict(entry)
+	}
+}
+
+// evict drops the instance of a cached factory and disposes it.
+func (c *Container) evict(entry *lruEntry) {
+
+	instance := entry.factory.instance.Swap(nil)
+	{
+		if instance == nil {
+			return
+		}
+	}
+
+	c.mu.Lock()
+	c.materialized = slices.DeleteFunc(c.materialized, func(t typeof) bool {
+		return t == entry.typeof
+	})
+	c.mu.Unlock()
+
+	if disposable, ok := (*instance).(Disposable); ok {
+		if err := disposable.Dispose(); err != nil && c.logger != nil {
+			c.logger("error", "dispose failed", "type", c.name(entry.typeof), "error", err)
+		}
+	}
+}
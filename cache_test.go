@@ -0,0 +1,68 @@
+package goinject
+
+import (
+	"reflect"
+	"testing"
+)
+
+type (
+	TestCachedA struct{ TestDisposable }
+	TestCachedB struct{ TestDisposable }
+	TestCachedC struct{ TestDisposable }
+)
+
+func TestWithMaxCachedInstances(t *testing.T) {
+	var disposed []string
+	builds := map[string]int{}
+
+	c := New(WithMaxCachedInstances(2))
+
+	if err := c.RegisterCached(func() *TestCachedA {
+		builds["a"]++
+		return &TestCachedA{TestDisposable{Name: "a", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("RegisterCached() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterCached(func() *TestCachedB {
+		builds["b"]++
+		return &TestCachedB{TestDisposable{Name: "b", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("RegisterCached() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterCached(func() *TestCachedC {
+		builds["c"]++
+		return &TestCachedC{TestDisposable{Name: "c", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("RegisterCached() unexpected error = %v", err)
+	}
+
+	first := MustGet[TestCachedA](c)
+	_ = MustGet[TestCachedB](c)
+
+	if again := MustGet[TestCachedA](c); again != first {
+		t.Errorf("Get[A]() within the limit got = %p, want cached %p", again, first)
+	}
+
+	// B is now the least recently resolved entry.
+	_ = MustGet[TestCachedC](c)
+
+	if want := []string{"b"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("evicted = %v, want %v", disposed, want)
+	}
+
+	_ = MustGet[TestCachedB](c)
+
+	if want := []string{"b", "a"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("evicted = %v, want %v", disposed, want)
+	}
+
+	if rebuilt := MustGet[TestCachedA](c); rebuilt == first {
+		t.Error("Get[A]() after eviction should rebuild the instance")
+	}
+
+	if want := map[string]int{"a": 2, "b": 2, "c": 1}; !reflect.DeepEqual(builds, want) {
+		t.Errorf("builds = %v, want %v", builds, want)
+	}
+}
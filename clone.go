@@ -0,0 +1,135 @@
+package goinject
+
+import (
+	"context"
+	"reflect"
+)
+
+// CloneOption changes how RegisterCloned copies its template.
+type CloneOption func(*cloneOptions)
+
+// cloneOptions is the set of CloneOption applied to a RegisterCloned call.
+type cloneOptions struct {
+	deep bool
+}
+
+// DeepClone makes RegisterCloned copy what the template points to as well: pointers,
+// slices, maps and interfaces held by exported fields are copied recursively, so a clone
+// shares no memory with the template through them. Unexported fields are copied shallowly.
+func DeepClone() CloneOption {
+	return func(o *cloneOptions) {
+		o.deep = true
+	}
+}
+
+// RegisterCloned registers a template whose every resolution returns a new copy of it,
+// for default-configured objects that callers customize. The copy is shallow unless
+// DeepClone is given. Like transient instances, clones are not disposed on Close.
+//
+// Example:
+//
+//	goinject.RegisterCloned(container, &Request{Timeout: time.Second}, goinject.DeepClone())
+//	request, _ := goinject.Get[Request](container) // a copy, free to customize
+func RegisterCloned[T any](c *Container, template *T, opts ...CloneOption) error {
+
+	var o cloneOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	typeof := reflect.TypeFor[*T]()
+
+	f := newFactory(typeof, func(context.Context, *Container) (any, error) {
+
+		if !o.deep {
+			clone := *template
+			return &clone, nil
+		}
+
+		return deepCopy(reflect.ValueOf(template), map[copiedKey]reflect.Value{}).Interface(), nil
+	})
+	f.lifetime = Transient
+
+	return c.addFactory(typeof, f)
+}
+
+// copiedKey identifies a pointer copied by deepCopy. A struct and its first field share
+// their address, so the type is part of the key.
+type copiedKey struct {
+	addr   uintptr
+	typeof typeof
+}
+
+// deepCopy copies the value and, recursively, what it refers to.
+// Pointers already copied are reused, so shared and cyclic structures keep their shape.
+func deepCopy(src reflect.Value, copied map[copiedKey]reflect.Value) reflect.Value {
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return src
+		}
+
+		key := copiedKey{src.Pointer(), src.Type()}
+		if dst, ok := copied[key]; ok {
+			return dst
+		}
+
+		dst := reflect.New(src.Type().Elem())
+		copied[key] = dst
+		dst.Elem().Set(deepCopy(src.Elem(), copied))
+
+		return dst
+	case reflect.Struct:
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(src)
+
+		for i := range src.NumField() {
+			if dst.Field(i).CanSet() {
+				dst.Field(i).Set(deepCopy(src.Field(i), copied))
+			}
+		}
+
+		return dst
+	case reflect.Slice:
+		if src.IsNil() {
+			return src
+		}
+
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i), copied))
+		}
+
+		return dst
+	case reflect.Array:
+		dst := reflect.New(src.Type()).Elem()
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i), copied))
+		}
+
+		return dst
+	case reflect.Map:
+		if src.IsNil() {
+			return src
+		}
+
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+		for iter := src.MapRange(); iter.Next(); {
+			dst.SetMapIndex(deepCopy(iter.Key(), copied), deepCopy(iter.Value(), copied))
+		}
+
+		return dst
+	case reflect.Interface:
+		if src.IsNil() {
+			return src
+		}
+
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(deepCopy(src.Elem(), copied))
+
+		return dst
+	default:
+		return src
+	}
+}
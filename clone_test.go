@@ -0,0 +1,97 @@
+package goinject
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TestTemplate struct {
+	Name    string
+	Tags    []string
+	Limits  map[string]int
+	Service *TestService
+}
+
+func TestRegisterCloned(t *testing.T) {
+	newTemplate := func() *TestTemplate {
+		return &TestTemplate{
+			Name:    "default",
+			Tags:    []string{"a", "b"},
+			Limits:  map[string]int{"rps": 10},
+			Service: &TestService{Name: "shared"},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		opts       []CloneOption
+		wantShared bool
+	}{
+		{"shallow", nil, true},
+		{"deep", []CloneOption{DeepClone()}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			template := newTemplate()
+
+			if err := RegisterCloned(c, template, tt.opts...); err != nil {
+				t.Fatalf("RegisterCloned() unexpected error = %v", err)
+			}
+
+			first := MustGet[TestTemplate](c)
+			second := MustGet[TestTemplate](c)
+
+			if first == second || first == template {
+				t.Fatal("Get[T]() should return a new clone on every call")
+			}
+
+			if !reflect.DeepEqual(first, template) || !reflect.DeepEqual(second, template) {
+				t.Errorf("Get[T]() got = %+v, %+v, want clones equal to %+v", first, second, template)
+			}
+
+			if shared := first.Service == template.Service; shared != tt.wantShared {
+				t.Errorf("clone shares the service pointer = %v, want %v", shared, tt.wantShared)
+			}
+
+			first.Tags[0] = "changed"
+			first.Limits["rps"] = 1
+
+			if changed := template.Tags[0] == "changed" && template.Limits["rps"] == 1; changed != tt.wantShared {
+				t.Errorf("changing the clone changed the template = %v, want %v", changed, tt.wantShared)
+			}
+		})
+	}
+}
+
+func TestRegisterCloned_Aliasing(t *testing.T) {
+	type (
+		Inner    struct{ X int }
+		Template struct {
+			Inner *Inner
+			X     *int
+			Again *Inner
+		}
+	)
+
+	inner := &Inner{X: 1}
+	// Inner and X point to the same address with different types.
+	template := &Template{Inner: inner, X: &inner.X, Again: inner}
+
+	c := New()
+
+	if err := RegisterCloned(c, template, DeepClone()); err != nil {
+		t.Fatalf("RegisterCloned() unexpected error = %v", err)
+	}
+
+	clone := MustGet[Template](c)
+
+	if clone.Inner == inner || clone.Inner.X != 1 || *clone.X != 1 {
+		t.Errorf("Get[T]() got = %+v, want a deep copy of %+v", clone, template)
+	}
+
+	if clone.Again != clone.Inner {
+		t.Error("clone should keep the pointers shared by the template shared")
+	}
+}
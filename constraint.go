@@ -0,0 +1,33 @@
+package goinject
+
+import (
+	"context"
+	"reflect"
+)
+
+// Numeric is the set of built-in integer and floating-point types, and the types based on them.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// RegisterFactoryConstrained registers a factory for *T where T must satisfy Numeric,
+// so that generic library code cannot register anything else.
+//
+// Example:
+//
+//	type Port int
+//
+//	goinject.RegisterFactoryConstrained(container, func() *Port {
+//	    port := Port(8080)
+//	    return &port
+//	})
+func RegisterFactoryConstrained[T Numeric](c *Container, factory func() *T) error {
+
+	typeof := reflect.TypeFor[*T]()
+
+	return c.addFactory(typeof, newFactory(typeof, func(context.Context, *Container) (any, error) {
+		return factory(), nil
+	}))
+}
@@ -0,0 +1,35 @@
+package goinject
+
+import (
+	"testing"
+)
+
+func TestRegisterFactoryConstrained(t *testing.T) {
+	type Port int
+
+	c := New()
+
+	if err := RegisterFactoryConstrained(c, func() *Port {
+		port := Port(8080)
+		return &port
+	}); err != nil {
+		t.Fatalf("RegisterFactoryConstrained() unexpected error = %v", err)
+	}
+
+	if err := RegisterFactoryConstrained(c, func() *float64 {
+		ratio := 0.5
+		return &ratio
+	}); err != nil {
+		t.Fatalf("RegisterFactoryConstrained() unexpected error = %v", err)
+	}
+
+	port, err := Get[Port](c)
+	if err != nil || *port != 8080 {
+		t.Errorf("Get[Port]() got = %v, %v, want %v", port, err, 8080)
+	}
+
+	ratio, err := Get[float64](c)
+	if err != nil || *ratio != 0.5 {
+		t.Errorf("Get[float64]() got = %v, %v, want %v", ratio, err, 0.5)
+	}
+}
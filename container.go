@@ -1,9 +1,16 @@
 package goinject
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type (
@@ -16,25 +23,141 @@ var (
 	ErrFactoryMustReturnOneValue  = errors.New("factory must return one value")
 	ErrFactoryMustTakeNoArguments = errors.New("factory must take no arguments")
 	ErrOutputMustBeAPointer       = errors.New("output must be a pointer")
+	ErrPlaceholderUnset           = errors.New("placeholder is not set")
+	ErrReentrantResolution        = errors.New("reentrant resolution")
+	ErrConstructorMustReturnValue = errors.New("constructor must return a value and an optional error")
+	ErrContainerClosed            = errors.New("container is closed")
+	ErrContainerFrozen            = errors.New("container is frozen")
+	ErrAmbiguousResolution        = errors.New("ambiguous resolution")
+	ErrNotAnInterface             = errors.New("is not an interface")
+	ErrDoesNotImplement           = errors.New("does not implement")
+	ErrCircularDependency         = errors.New("circular dependency")
+	ErrInvokeMustReturnError      = errors.New("function must return nothing or an error")
+	ErrNilFactoryResult           = errors.New("factory returned nil")
+	ErrResolutionTimeout          = errors.New("resolution timed out")
+	ErrInvalidSnapshot            = errors.New("invalid snapshot")
+	ErrNotMaterialized            = errors.New("service is not materialized")
+	ErrInstanceRegistered         = errors.New("an instance is already registered")
+	ErrPartialImplementation      = errors.New("interface is only implemented in parts")
+	ErrNoBinding                  = errors.New("no binding for interface")
+	ErrInstanceType               = errors.New("instance is not of the registered type")
 )
 
+// registry is an immutable view of the registrations, published by Freeze
+// when the container is created with WithCopyOnWrite.
+type registry struct {
+	providers map[typeof]any
+	factories map[typeof]*factory
+	bindings  map[typeof]typeof
+}
+
+// locker guards the registrations of a container, see WithoutLocking.
+type locker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// noLock is the locker of a container created with WithoutLocking.
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
 type Container struct {
-	factories map[typeof]func() any
+	factories map[typeof]*factory
 	providers map[typeof]any
-	mu        sync.RWMutex
+	bindings  map[typeof]typeof
+	order     []typeof
+	invokers  []Invoker
+	mu        locker
+
+	finalizers   map[typeof]func(any) error
+	hints        map[typeof]int
+	priorities   map[typeof]int
+	groups       map[string][]any
+	typedGroups  map[any][]any
+	tags         map[string][]typeof
+	materialized []typeof
+	frozen       bool
+	closed       atomic.Bool
+	disposed     atomic.Bool
+	disposedOf   []typeof
+	snapshot     atomic.Pointer[registry]
+
+	named          map[namedKey]any
+	namedFactories map[typeof]*namedFactory
+	namedCtors     map[namedKey]*factory
+	keyed          map[typeof]any
+	built          []func(*Container) error
+
+	metrics metrics
+	events  events
+
+	parent *Container
+	scoped map[typeof]*factory
+	opts   []Option
+
+	autoPointer       bool
+	copyOnWrite       bool
+	structural        bool
+	onMissing         func(reflect.Type) (any, bool)
+	fallback          func(reflect.Type) (any, bool)
+	reflectiveDefault bool
+	logger            func(level, msg string, kv ...any)
+	profiles          map[string]bool
+	maxCached         int
+	rejectNil         bool
+	postResolve       []func(reflect.Type, any) any
+	onResolve         func(reflect.Type, time.Duration, bool, error)
+	onRegister        []func(reflect.Type, any) (any, error)
+	strict            bool
+	strictInterfaces  bool
+	typeName          func(reflect.Type) string
+	graceful          bool
+	onGraceful        func(dep, of reflect.Type)
+	stats             bool
+	cache             lru
 }
 
-// New creates a new Container instance.
+// New creates a new Container instance configured with the given options.
 // It returns a pointer to the Container.
 //
 // Example:
 //
 //	container := goinject.New()
-func New() *Container {
-	return &Container{
-		factories: make(map[typeof]func() any),
+func New(opts ...Option) *Container {
+
+	c := &Container{
+		factories: make(map[typeof]*factory),
 		providers: make(map[typeof]any),
+		bindings:  make(map[typeof]typeof),
+		mu:        &sync.RWMutex{},
+
+		finalizers:  make(map[typeof]func(any) error),
+		hints:       make(map[typeof]int),
+		priorities:  make(map[typeof]int),
+		groups:      make(map[string][]any),
+		typedGroups: make(map[any][]any),
+		tags:        make(map[string][]typeof),
+
+		named:          make(map[namedKey]any),
+		namedFactories: make(map[typeof]*namedFactory),
+		namedCtors:     make(map[namedKey]*factory),
+		keyed:          make(map[typeof]any),
+
+		scoped: make(map[typeof]*factory),
+		opts:   opts,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // RegisterFactory registers a factory function that returns a new instance of the given type.
@@ -47,63 +170,223 @@ func New() *Container {
 //	})
 func (c *Container) RegisterFactory(factory any) error {
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.addFactory(typeof, f)
+}
 
-	factoryValue := reflect.ValueOf(factory)
+// parseFactory validates a factory function and wraps it into a factory entry.
+// It returns the type the factory produces.
+func (c *Container) parseFactory(fn any) (typeof, *factory, error) {
+
+	factoryValue := reflect.ValueOf(fn)
 
 	factoryType := factoryValue.Type()
 	{
 		if factoryType.Kind() != reflect.Func {
-			return ErrFactoryMustBeAFunction
+			return nil, nil, fmt.Errorf("%w, got %s", ErrFactoryMustBeAFunction, c.name(factoryType))
 		}
 
 		if factoryType.NumIn() != 0 {
-			return ErrFactoryMustTakeNoArguments
+			return nil, nil, fmt.Errorf("%w, got %s", ErrFactoryMustTakeNoArguments, c.name(factoryType))
 		}
 
 		if factoryType.NumOut() != 1 {
-			return ErrFactoryMustReturnOneValue
+			return nil, nil, fmt.Errorf("%w, got %s", ErrFactoryMustReturnOneValue, c.name(factoryType))
 		}
 	}
 
 	typeof := factoryType.Out(0)
 
 	if typeof.Kind() != reflect.Ptr {
-		return ErrOutputMustBeAPointer
+		return nil, nil, fmt.Errorf("%w, got %s", ErrOutputMustBeAPointer, c.name(factoryType))
+	}
+
+	return typeof, newFactory(typeof, func(context.Context, *Container) (any, error) {
+		return factoryValue.Call(nil)[0].Interface(), nil
+	}), nil
+}
+
+// SwapFactory replaces whatever is registered under the factory's return type with the factory.
+// The cached singleton is evicted in the same step, so the next resolution builds from the
+// new factory while callers already holding the previous instance keep using it.
+//
+// Example:
+//
+//	container.SwapFactory(func() *Config {
+//	    return loadConfig()
+//	})
+func (c *Container) SwapFactory(factory any) error {
+
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	c.hooked(typeof, f)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	if _, ok := c.providers[typeof]; ok {
+		delete(c.providers, typeof)
+		delete(c.finalizers, typeof)
+
+		c.materialized = slices.DeleteFunc(c.materialized, func(t reflect.Type) bool {
+			return t == typeof
+		})
+	}
+
+	c.putFactory(typeof, f)
+
+	return nil
+}
+
+// RegisterFactoryOrReplace registers a factory like RegisterFactory, replacing an instance
+// registered for the same type instead of being shadowed by it. It behaves like SwapFactory.
+//
+// Example:
+//
+//	container.Register(&Config{Env: "dev"})
+//	container.RegisterFactoryOrReplace(loadConfig)
+func (c *Container) RegisterFactoryOrReplace(factory any) error {
+	return c.SwapFactory(factory)
+}
+
+// EnsureDefault registers the factory only if nothing is registered yet for the type it returns.
+// Libraries use it to provide a default that applications override by registering first.
+// The factory may take dependencies and return an error, like a constructor passed to Provide.
+//
+// Example:
+//
+//	// in the library
+//	container.EnsureDefault(func() *Logger {
+//	    return NewStderrLogger()
+//	})
+func (c *Container) EnsureDefault(factory any) error {
+
+	typeof, f, err := c.parseConstructor(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	c.hooked(typeof, f)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
 	}
 
-	c.factories[typeof] = func() any {
-		return factoryValue.Call(nil)[0].Interface()
+	if !c.registered(typeof) {
+		c.putFactory(typeof, f)
 	}
 
 	return nil
 }
 
 // Register registers a singleton instance of the given type.
-// It returns an error if the input is not a pointer.
+// It returns an error if the input is not a pointer, unless the container
+// was created with WithAutoPointer.
 //
 // Example:
 //
 //	container.Register(&User{ID: 1, Name: "John", Age: 25, Salary: 50000.0})
 func (c *Container) Register(service any) error {
-	typeof := reflect.TypeOf(service)
+
+	typeof, service, err := c.provider(service)
 	{
-		if typeof.Kind() != reflect.Ptr {
-			return ErrOutputMustBeAPointer
+		if err != nil {
+			return err
 		}
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	return c.addProvider(typeof, service)
+}
 
-	c.providers[typeof] = service
+// registering passes an instance through the hooks set by WithRegistrationHook.
+func (c *Container) registering(typeof typeof, instance any) (any, error) {
 
-	return nil
+	given := reflect.TypeOf(instance)
+
+	for _, hook := range c.onRegister {
+		var err error
+
+		instance, err = hook(typeof, instance)
+		{
+			if err != nil {
+				return nil, fmt.Errorf("registration of %s: %w", c.name(typeof), err)
+			}
+
+			if reflect.TypeOf(instance) != given {
+				return nil, fmt.Errorf("registration of %s: hook returned %s, want the same type", c.name(typeof), c.nameOf(instance))
+			}
+		}
+	}
+
+	return instance, nil
+}
+
+// hooked makes the factory pass every instance it builds through the hooks set by
+// WithRegistrationHook. It must be called once per factory, before it is stored.
+func (c *Container) hooked(typeof typeof, f *factory) {
+
+	if len(c.onRegister) == 0 {
+		return
+	}
+
+	call := f.call
+	f.call = func(ctx context.Context, r *Container) (any, error) {
+
+		instance, err := call(ctx, r)
+		{
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return c.registering(typeof, instance)
+	}
+}
+
+// provider validates an instance passed to Register and returns the type it is registered under.
+func (c *Container) provider(service any) (typeof, any, error) {
+
+	typeof := reflect.TypeOf(service)
+	{
+		if typeof.Kind() != reflect.Ptr && c.autoPointer {
+			servicePtr := reflect.New(typeof)
+			servicePtr.Elem().Set(reflect.ValueOf(service))
+			service, typeof = servicePtr.Interface(), servicePtr.Type()
+		}
+
+		if typeof.Kind() != reflect.Ptr {
+			return nil, nil, ErrOutputMustBeAPointer
+		}
+	}
+
+	return typeof, service, nil
 }
 
 // Get retrieves a dependency of the given type from the container.
 // It returns an error if the dependency is not found.
+// When out points to an interface, the resolved service is also assigned through out;
+// without a registration under the interface itself, it is resolved to the single registered
+// type implementing it, and fails with ErrAmbiguousResolution if several do.
 //
 // Example:
 //
@@ -114,59 +397,990 @@ func (c *Container) Register(service any) error {
 //	}
 //	fmt.Println(user.Name) // Prints: John
 func (c *Container) Get(out any) (any, error) {
+	return c.GetContext(context.Background(), out)
+}
+
+// GetOrElse retrieves a dependency like Get, but asks the fallback when nothing
+// is registered for it. The fallback is used for this call only, its result is not registered.
+//
+// Example:
+//
+//	_, err := container.GetOrElse(&logger, func(reflect.Type) (any, error) {
+//	    return log.Default(), nil
+//	})
+func (c *Container) GetOrElse(out any, fallback func(reflect.Type) (any, error)) (any, error) {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := c.resolveOut(context.Background(), typeof)
+	if absent(err, typeof) {
+		service, err = fallback(typeof)
+		if err == nil && (service == nil || !reflect.TypeOf(service).AssignableTo(typeof)) {
+			err = fmt.Errorf("fallback for %s returned %s: %w", c.name(typeof), c.nameOf(service), ErrDoesNotImplement)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if typeof.Kind() == reflect.Interface {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(service))
+	}
+
+	return service, nil
+}
+
+// resolveOut resolves the type requested through an out pointer. An interface nothing is
+// registered under resolves to the single registration implementing it, as the out pointer
+// tells which interface the caller wants, unless WithStrictInterfaces asks for a binding.
+func (c *Container) resolveOut(ctx context.Context, typeof typeof) (any, error) {
+
+	service, err := c.resolve(ctx, typeof)
+	if typeof.Kind() != reflect.Interface || !absent(err, typeof) || c.strictInterfaces {
+		return service, err
+	}
+
+	switch candidates := c.assignable(typeof); len(candidates) {
+	case 0:
+		return nil, err
+	case 1:
+		return c.resolve(ctx, candidates[0])
+	default:
+		return nil, fmt.Errorf("%w: %s is implemented by %s", ErrAmbiguousResolution, c.name(typeof), c.names(candidates))
+	}
+}
+
+// keyOf returns the type a service requested through the out pointer is registered under:
+// the pointer type itself for concrete services, the interface type for interface services.
+func keyOf(out any) (typeof, error) {
 
 	typeof := reflect.TypeOf(out)
 	{
-		if typeof.Kind() != reflect.Ptr {
+		if typeof == nil || typeof.Kind() != reflect.Ptr {
 			return nil, ErrOutputMustBeAPointer
 		}
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if typeof.Elem().Kind() == reflect.Interface {
+		return typeof.Elem(), nil
+	}
 
-	service, ok := c.providers[typeof]
+	return typeof, nil
+}
+
+// resolve returns the service registered under the given type,
+// building it from its factory when no instance is registered.
+func (c *Container) resolve(ctx context.Context, typeof typeof) (any, error) {
 
-	if !ok {
-		if factory := c.factories[typeof]; factory != nil {
-			service = factory()
-		} else {
-			return nil, ErrServiceNotFound
+	if c.stats {
+		c.metrics.of(typeof).resolutions.Add(1)
+	}
+
+	observed := c.events.active.Load()
+
+	if c.logger == nil && !observed && c.onResolve == nil {
+		return c.lookup(ctx, typeof)
+	}
+
+	// The build counter tells whether the lookup built the instance, see measured.
+	metrics := c.metrics.of(typeof)
+	start, builds := time.Now(), metrics.builds.Load()
+
+	service, err := c.lookup(ctx, typeof)
+
+	if observed || c.onResolve != nil {
+		elapsed, built := time.Since(start), metrics.builds.Load() != builds
+
+		if observed {
+			c.events.emit(ResolveEvent{typeof, elapsed, built, err})
 		}
+
+		if c.onResolve != nil {
+			c.onResolve(typeof, elapsed, !built && err == nil, err)
+		}
+	}
+
+	if c.logger == nil {
+		return service, err
 	}
 
-	if !ok {
-		c.providers[typeof] = service
+	if err != nil {
+		c.logger("error", "resolve failed", "type", c.name(typeof), "duration", time.Since(start), "error", err)
+		return nil, err
 	}
 
+	c.logger("debug", "resolve", "type", c.name(typeof), "duration", time.Since(start))
+
 	return service, nil
 }
 
-// GetValue retrieves a dependency and copies its value into the provided pointer.
-// It returns an error if the dependency is not found.
-//
-// Example:
-//
-//	var user User
-//	err := container.GetValue(&user)
-//	if err != nil {
-//	    log.Fatal(err)
-//	}
-//	fmt.Println(user.Name) // Prints: John
-func (c *Container) GetValue(out any) error {
+// lookup finds the registration for the given type and resolves it.
+func (c *Container) lookup(ctx context.Context, typeof typeof) (any, error) {
+
+	var (
+		service any
+		ok      bool
+		factory *factory
+		target  reflect.Type
+	)
+
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		service, ok = snapshot.providers[typeof]
+		factory = snapshot.factories[typeof]
+		target = snapshot.bindings[typeof]
+	} else {
+		c.mu.RLock()
+		service, ok = c.providers[typeof]
+		factory = c.factories[typeof]
+		target = c.bindings[typeof]
+		c.mu.RUnlock()
+	}
+
+	if c.closed.Load() {
+		return c.remaining(ctx, typeof, service, ok, factory, target)
+	}
+
+	if ok {
+		return service, nil
+	}
+
+	if factory == nil {
+		if target != nil {
+			return c.resolve(ctx, target)
+		}
+
+		if c.parent != nil {
+			return c.parent.lookupFor(ctx, typeof, c)
+		}
+
+		return c.resolveMissing(ctx, typeof)
+	}
+
+	if factory.lifetime == Scoped {
+		return c.build(ctx, typeof, c.scopedFactory(typeof, factory))
+	}
+
+	return c.build(ctx, typeof, factory)
+}
+
+// remaining resolves a registration of a closed container. While Close disposes,
+// the instances that already exist stay resolvable so that teardown code can reach
+// its collaborators; nothing new is constructed. A closed scope also reaches the
+// instances that exist in its parents.
+func (c *Container) remaining(ctx context.Context, typeof typeof, service any, ok bool, factory *factory, target typeof) (any, error) {
+
+	if c.disposed.Load() {
+		return nil, ErrContainerClosed
+	}
+
+	if ok {
+		return service, nil
+	}
+
+	if factory != nil {
+		if instance, built := factory.cached(); built {
+			return instance, nil
+		}
+	}
+
+	if target != nil {
+		return c.resolve(ctx, target)
+	}
+
+	if c.parent != nil {
+		if instance, ok := c.parent.existing(typeof, c); ok {
+			return instance, nil
+		}
+	}
+
+	return nil, ErrContainerClosed
+}
+
+// existing returns the instance of the given type that already exists for the scope,
+// looking it up in c and its parents like lookupFor does, without building anything.
+func (c *Container) existing(typeof typeof, scope *Container) (any, bool) {
+
+	if c.disposed.Load() {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	service, ok := c.providers[typeof]
+	factory := c.factories[typeof]
+	target := c.bindings[typeof]
+	c.mu.RUnlock()
+
+	switch {
+	case ok:
+		return service, true
+	case factory != nil && factory.lifetime == Scoped:
+		scope.mu.RLock()
+		local := scope.scoped[typeof]
+		scope.mu.RUnlock()
+
+		if local == nil {
+			return nil, false
+		}
+
+		return local.cached()
+	case factory != nil:
+		return factory.cached()
+	case target != nil:
+		return scope.existing(target, scope)
+	case c.parent != nil:
+		return c.parent.existing(typeof, scope)
+	default:
+		return nil, false
+	}
+}
+
+// lookupFor resolves a type the scope has no registration for from the registrations of c.
+// Bindings and scoped factories are resolved in the scope, everything else in c.
+func (c *Container) lookupFor(ctx context.Context, typeof typeof, scope *Container) (any, error) {
+
+	c.mu.RLock()
+	service, ok := c.providers[typeof]
+	factory := c.factories[typeof]
+	target := c.bindings[typeof]
+	c.mu.RUnlock()
+
+	switch {
+	case c.closed.Load():
+		return nil, ErrContainerClosed
+	case ok:
+		return service, nil
+	case factory != nil && factory.lifetime == Scoped:
+		return scope.build(ctx, typeof, scope.scopedFactory(typeof, factory))
+	case factory != nil:
+		return c.build(ctx, typeof, factory)
+	case target != nil:
+		return scope.resolve(ctx, target)
+	case c.parent != nil:
+		return c.parent.lookupFor(ctx, typeof, scope)
+	default:
+		return c.resolveMissing(ctx, typeof)
+	}
+}
+
+// scopedFactory returns the copy of a scoped factory that caches its instance in c.
+func (c *Container) scopedFactory(typeof typeof, f *factory) *factory {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	local := c.scoped[typeof]
+	if local == nil {
+		local = f.fresh()
+		c.scoped[typeof] = local
+	}
+
+	return local
+}
+
+// resolveMissing resolves a type that has no registration of its own,
+// through structural resolution and then the on-missing hook.
+func (c *Container) resolveMissing(ctx context.Context, typeof typeof) (any, error) {
+
+	if c.strictInterfaces && typeof.Kind() == reflect.Interface {
+		return nil, c.unbound(typeof)
+	}
+
+	if c.structural && typeof.Kind() == reflect.Interface {
+		switch candidates := c.assignable(typeof); len(candidates) {
+		case 0:
+			// Nothing implements it, give the hook a chance.
+		case 1:
+			return c.resolve(ctx, candidates[0])
+		default:
+			return nil, fmt.Errorf("%w: %s is implemented by %s", ErrAmbiguousResolution, c.name(typeof), c.names(candidates))
+		}
+	}
+
+	if c.onMissing != nil {
+		if service, ok := c.onMissing(typeof); ok {
+			if serviceType := reflect.TypeOf(service); serviceType == nil || !serviceType.AssignableTo(typeof) {
+				return nil, fmt.Errorf("on-missing hook returned %s for %s", c.nameOf(service), c.name(typeof))
+			}
+
+			return c.remember(typeof, service)
+		}
+	}
+
+	if c.fallback != nil {
+		if service, ok := c.fallback(typeof); ok {
+			if serviceType := reflect.TypeOf(service); serviceType == nil || !serviceType.AssignableTo(typeof) {
+				return nil, fmt.Errorf("fallback provider returned %s for %s", c.nameOf(service), c.name(typeof))
+			}
+
+			return service, nil
+		}
+	}
+
+	if c.defaultable(typeof) {
+		return c.remember(typeof, reflect.New(typeof.Elem()).Interface())
+	}
+
+	if typeof.Kind() == reflect.Interface {
+		c.mu.RLock()
+		err := c.composite(typeof)
+		c.mu.RUnlock()
 
-	service, err := c.Get(out)
-	{
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	servicePtr := reflect.ValueOf(service).Elem()
+	return nil, ErrServiceNotFound
+}
 
-	setOutValue := reflect.ValueOf(out).Elem()
+// defaultable reports whether a missing type is built as a zero value, see WithReflectiveDefault.
+func (c *Container) defaultable(typeof typeof) bool {
+	return c.reflectiveDefault && typeof.Kind() == reflect.Ptr && typeof.Elem().Kind() == reflect.Struct
+}
 
-	setOutValue.Set(servicePtr)
+// unbound reports an interface requested without an explicit registration under strict interfaces.
+func (c *Container) unbound(iface typeof) error {
+	return &unboundError{iface, fmt.Errorf("%w %s: %w", ErrNoBinding, c.name(iface), ErrServiceNotFound)}
+}
 
-	return nil
+// unboundError is the error of an interface without binding under WithStrictInterfaces.
+// It keeps the interface so that the absence of the requested type itself can be told
+// from a miss further down the graph.
+type unboundError struct {
+	iface typeof
+	err   error
+}
+
+func (e *unboundError) Error() string { return e.err.Error() }
+func (e *unboundError) Unwrap() error { return e.err }
+
+// absent reports whether err is the miss of typeof itself rather than of one of its dependencies,
+// whose misses are wrapped with the dependency chain.
+func absent(err error, typeof typeof) bool {
+
+	if err == ErrServiceNotFound {
+		return true
+	}
+
+	unbound, ok := err.(*unboundError)
+
+	return ok && unbound.iface == typeof
+}
+
+// composite reports an interface that no registration implements, while registrations
+// together provide all of its methods. Such composites are never synthesized from their parts.
+// It must be called with the lock held.
+func (c *Container) composite(iface typeof) error {
+
+	var parts []typeof
+	provided := make(map[string]bool)
+
+	for _, typeof := range c.order {
+		if _, alias := c.bindings[typeof]; alias {
+			continue
+		}
+
+		if typeof.Implements(iface) {
+			return nil
+		}
+
+		found := false
+		for i := range iface.NumMethod() {
+			if method := iface.Method(i); hasMethod(typeof, method) {
+				provided[method.Name], found = true, true
+			}
+		}
+
+		if found {
+			parts = append(parts, typeof)
+		}
+	}
+
+	if len(parts) < 2 || len(provided) < iface.NumMethod() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s needs one registration implementing all of it, %s each implement some of it",
+		ErrPartialImplementation, c.name(iface), c.names(parts))
+}
+
+// hasMethod reports whether the type has a method with the name and signature of the interface method.
+func hasMethod(typeof typeof, method reflect.Method) bool {
+
+	m, ok := typeof.MethodByName(method.Name)
+	{
+		if !ok {
+			return false
+		}
+	}
+
+	// Methods of concrete types take their receiver first, interface methods do not.
+	in := m.Type.NumIn()
+	offset := 0
+	if typeof.Kind() != reflect.Interface {
+		offset = 1
+	}
+
+	if in-offset != method.Type.NumIn() || m.Type.NumOut() != method.Type.NumOut() || m.Type.IsVariadic() != method.Type.IsVariadic() {
+		return false
+	}
+
+	for i := range method.Type.NumIn() {
+		if m.Type.In(i+offset) != method.Type.In(i) {
+			return false
+		}
+	}
+
+	for i := range method.Type.NumOut() {
+		if m.Type.Out(i) != method.Type.Out(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// assignable returns the registered types assignable to the given type, in registration order.
+func (c *Container) assignable(elem typeof) []typeof {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.matching(elem)
+}
+
+// matching returns the registered types assignable to the given type, in registration order.
+// Interface bindings are skipped, their concrete registration is matched instead.
+// It must be called with the lock held.
+func (c *Container) matching(elem typeof) []typeof {
+
+	var types []typeof
+	for _, typeof := range c.order {
+		if _, alias := c.bindings[typeof]; alias {
+			continue
+		}
+
+		if typeof == elem || elem.Kind() == reflect.Interface && typeof.Implements(elem) {
+			types = append(types, typeof)
+		}
+	}
+
+	return types
+}
+
+// collect resolves every registration assignable to the given type, in registration order.
+func (c *Container) collect(ctx context.Context, elem typeof) ([]any, error) {
+
+	types := c.assignable(elem)
+
+	services := make([]any, 0, len(types))
+
+	for _, typeof := range types {
+		service, err := c.resolve(ctx, typeof)
+		{
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		services = append(services, service)
+	}
+
+	return services, nil
+}
+
+// DeepCopier is implemented by services whose values hold pointers that a copy must not share.
+// DeepCopy returns an independent copy, either as a value or as a pointer to it.
+type DeepCopier interface {
+	DeepCopy() any
+}
+
+// GetValue retrieves a dependency and copies its value into the provided pointer.
+// It returns an error if the dependency is not found. The copy is shallow unless the service
+// implements DeepCopier, in which case the value returned by DeepCopy is stored instead.
+//
+// Example:
+//
+//	var user User
+//	err := container.GetValue(&user)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(user.Name) // Prints: John
+func (c *Container) GetValue(out any) error {
+
+	service, err := c.Get(out)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	// Get already assigned interfaces through the out pointer.
+	if reflect.TypeOf(out).Elem().Kind() == reflect.Interface {
+		return nil
+	}
+
+	servicePtr := reflect.ValueOf(service).Elem()
+
+	setOutValue := reflect.ValueOf(out).Elem()
+
+	if copier, ok := service.(DeepCopier); ok {
+		raw := copier.DeepCopy()
+
+		copied := reflect.ValueOf(raw)
+		if copied.Kind() == reflect.Ptr && copied.Type().Elem() == setOutValue.Type() {
+			copied = copied.Elem()
+		}
+
+		if !copied.IsValid() || copied.Type() != setOutValue.Type() {
+			return fmt.Errorf("DeepCopy of %s returned %s", c.nameOf(service), c.nameOf(raw))
+		}
+
+		servicePtr = copied
+	}
+
+	setOutValue.Set(servicePtr)
+
+	return nil
+}
+
+// GetMany resolves a dependency into each of the outs and returns their errors, nil where the
+// resolution succeeded, at the positions of the outs. A pointer to a pointer variable receives
+// the shared instance, other outs are filled like GetValue fills them.
+//
+// Example:
+//
+//	var (
+//	    db     *DB
+//	    repo   UserRepository
+//	    config Config
+//	)
+//	errs := container.GetMany(&db, &repo, &config)
+//	if errs[2] != nil {
+//	    config = defaultConfig
+//	}
+func (c *Container) GetMany(outs ...any) []error {
+
+	errs := make([]error, len(outs))
+
+	for i, out := range outs {
+		value := reflect.ValueOf(out)
+
+		if value.Kind() != reflect.Ptr || value.IsNil() {
+			errs[i] = ErrOutputMustBeAPointer
+			continue
+		}
+
+		if value.Elem().Kind() != reflect.Ptr {
+			errs[i] = c.GetValue(out)
+			continue
+		}
+
+		service, err := c.resolve(context.Background(), value.Elem().Type())
+		{
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+		}
+
+		value.Elem().Set(reflect.ValueOf(service))
+	}
+
+	return errs
+}
+
+// Unregister removes every registration for the type of the given pointer.
+// It reports whether anything was removed; a frozen container removes nothing.
+//
+// Example:
+//
+//	removed := container.Unregister((*User)(nil))
+func (c *Container) Unregister(out any) bool {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return false
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.frozen || !c.registered(typeof) {
+		return false
+	}
+
+	delete(c.providers, typeof)
+	delete(c.factories, typeof)
+	delete(c.bindings, typeof)
+	delete(c.finalizers, typeof)
+	delete(c.hints, typeof)
+	delete(c.priorities, typeof)
+
+	for tag, types := range c.tags {
+		c.tags[tag] = slices.DeleteFunc(types, func(t reflect.Type) bool {
+			return t == typeof
+		})
+	}
+
+	c.order = slices.DeleteFunc(c.order, func(t reflect.Type) bool {
+		return t == typeof
+	})
+	c.materialized = slices.DeleteFunc(c.materialized, func(t reflect.Type) bool {
+		return t == typeof
+	})
+
+	return true
+}
+
+// RegisteredTypes returns every registered type sorted by name.
+//
+// Example:
+//
+//	for _, t := range container.RegisteredTypes() {
+//	    fmt.Println(t)
+//	}
+func (c *Container) RegisteredTypes() []reflect.Type {
+
+	types := c.RegisteredTypesOrdered()
+
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return types
+}
+
+// RegisteredTypesOrdered returns every registered type in the order it was first registered.
+//
+// Example:
+//
+//	for _, t := range container.RegisteredTypesOrdered() {
+//	    fmt.Println(t)
+//	}
+func (c *Container) RegisteredTypesOrdered() []reflect.Type {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.order)
+}
+
+// TypesOfKind returns the registered types of the given kind, in registration order,
+// without resolving anything. Pointer registrations are matched by the kind they point to,
+// so reflect.Struct finds every *T where T is a struct.
+//
+// Example:
+//
+//	for _, t := range container.TypesOfKind(reflect.Func) {
+//	    fmt.Println("handler:", t)
+//	}
+func (c *Container) TypesOfKind(kind reflect.Kind) []reflect.Type {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var types []reflect.Type
+	for _, typeof := range c.order {
+		if k := typeof.Kind(); k == kind || k == reflect.Ptr && typeof.Elem().Kind() == kind {
+			types = append(types, typeof)
+		}
+	}
+
+	return types
+}
+
+// registered reports whether the type has a provider, a factory or a binding.
+func (c *Container) registered(typeof typeof) bool {
+
+	if _, ok := c.providers[typeof]; ok {
+		return true
+	}
+
+	if _, ok := c.factories[typeof]; ok {
+		return true
+	}
+
+	_, ok := c.bindings[typeof]
+
+	return ok
+}
+
+// inherited reports whether a parent of c has a registration for the given type.
+func (c *Container) inherited(typeof typeof) bool {
+
+	for parent := c.parent; parent != nil; parent = parent.parent {
+		parent.mu.RLock()
+		ok := parent.registered(typeof)
+		parent.mu.RUnlock()
+
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addProvider passes a registered instance through the registration hooks and stores it under the given type.
+func (c *Container) addProvider(typeof typeof, service any) error {
+
+	service, err := c.registering(typeof, service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.setProvider(typeof, service)
+}
+
+// setProvider stores an instance that already went through the registration hooks.
+func (c *Container) setProvider(typeof typeof, service any) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.putProvider(typeof, service)
+
+	return nil
+}
+
+// putProvider stores a registered instance under the given type.
+// It must be called with the write lock held.
+func (c *Container) putProvider(typeof typeof, service any) {
+
+	c.materialize(typeof)
+	delete(c.finalizers, typeof)
+
+	c.track(typeof)
+	c.providers[typeof] = service
+
+	if c.logger != nil {
+		c.logger("debug", "register", "type", c.name(typeof), "kind", "instance")
+	}
+}
+
+// remember registers the instance resolveMissing produced for a type and returns the instance
+// registered for it, which is an earlier one if another resolution got there first. Unlike
+// registration it works on a frozen container, which keeps resolving; the snapshot published
+// by Freeze with WithCopyOnWrite is replaced so that the instance is found there too.
+func (c *Container) remember(typeof typeof, service any) (any, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed.Load() {
+		return nil, ErrContainerClosed
+	}
+
+	if existing, ok := c.providers[typeof]; ok {
+		return existing, nil
+	}
+
+	c.putProvider(typeof, service)
+
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		c.snapshot.Store(&registry{
+			providers: maps.Clone(c.providers),
+			factories: snapshot.factories,
+			bindings:  snapshot.bindings,
+		})
+	}
+
+	return service, nil
+}
+
+// writable reports why the registrations can no longer change, if they cannot.
+// It must be called with the lock held.
+func (c *Container) writable() error {
+
+	if c.closed.Load() {
+		return ErrContainerClosed
+	}
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
+	return nil
+}
+
+// addBinding makes the interface resolve to whatever is registered under the concrete type.
+func (c *Container) addBinding(iface, concrete typeof) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.track(iface)
+	c.bindings[iface] = concrete
+
+	if c.logger != nil {
+		c.logger("debug", "register", "type", c.name(iface), "kind", "binding", "concrete", c.name(concrete))
+	}
+
+	return nil
+}
+
+// name renders the type for diagnostics, with the formatter set by WithTypeNameFunc if any.
+func (c *Container) name(typeof typeof) string {
+
+	if typeof == nil {
+		return "<nil>"
+	}
+
+	if c.typeName != nil {
+		return c.typeName(typeof)
+	}
+
+	return typeof.String()
+}
+
+// nameOf renders the dynamic type of a value for diagnostics, like %T does, with the
+// formatter set by WithTypeNameFunc if any.
+func (c *Container) nameOf(value any) string {
+	return c.name(reflect.TypeOf(value))
+}
+
+// names renders a list of types for diagnostics, like fmt renders a slice.
+func (c *Container) names(types []typeof) string {
+
+	names := make([]string, len(types))
+	for i, typeof := range types {
+		names[i] = c.name(typeof)
+	}
+
+	return "[" + strings.Join(names, " ") + "]"
+}
+
+// instance returns the registered or built instance of the given type, or nil if there is none.
+// It must be called with the lock held.
+func (c *Container) instance(typeof typeof) any {
+
+	if service, ok := c.providers[typeof]; ok {
+		return service
+	}
+
+	if factory := c.factories[typeof]; factory != nil && factory.lifetime != Scoped {
+		if service, ok := factory.cached(); ok {
+			return service
+		}
+	}
+
+	if factory := c.scoped[typeof]; factory != nil {
+		if service, ok := factory.cached(); ok {
+			return service
+		}
+	}
+
+	return nil
+}
+
+// materialize records that an instance of the given type now exists.
+// It must be called with the write lock held.
+func (c *Container) materialize(typeof typeof) {
+	if !slices.Contains(c.materialized, typeof) {
+		c.materialized = append(c.materialized, typeof)
+	}
+}
+
+// addFactory stores a factory under the given type.
+func (c *Container) addFactory(typeof typeof, factory *factory) error {
+
+	c.hooked(typeof, factory)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	if err := c.shadowed(typeof); err != nil {
+		return err
+	}
+
+	c.putFactory(typeof, factory)
+
+	return nil
+}
+
+// shadowed reports a factory registered for a type that has an instance, which then keeps
+// precedence over the factory: a warning by default, ErrInstanceRegistered in strict mode.
+// It must be called with the lock held.
+func (c *Container) shadowed(typeof typeof) error {
+
+	if _, ok := c.providers[typeof]; !ok {
+		return nil
+	}
+
+	if c.strict {
+		return fmt.Errorf("%w for %s, use RegisterFactoryOrReplace to replace it", ErrInstanceRegistered, c.name(typeof))
+	}
+
+	if c.logger != nil {
+		c.logger("warn", "factory shadowed by instance", "type", c.name(typeof))
+	}
+
+	return nil
+}
+
+// putFactory stores a factory under the given type, dropping the instance built
+// by the factory it replaces. It must be called with the write lock held.
+func (c *Container) putFactory(typeof typeof, factory *factory) {
+
+	if _, ok := c.providers[typeof]; !ok && c.factories[typeof] != nil {
+		c.materialized = slices.DeleteFunc(c.materialized, func(t reflect.Type) bool {
+			return t == typeof
+		})
+	}
+
+	c.track(typeof)
+	c.factories[typeof] = factory
+
+	if c.logger != nil {
+		c.logger("debug", "register", "type", c.name(typeof), "kind", "factory")
+	}
+}
+
+// track records the registration order of a type the first time it is registered.
+// It must be called with the write lock held, before the registration is stored.
+func (c *Container) track(typeof typeof) {
+	if !c.registered(typeof) {
+		c.order = append(c.order, typeof)
+	}
+}
+
+// invoke runs the factory for the given type through the registered invokers.
+func (c *Container) invoke(ctx context.Context, typeof typeof, factory func(context.Context, *Container) (any, error)) (any, error) {
+
+	next := func() (any, error) {
+		return factory(ctx, c)
+	}
+
+	for i := len(c.invokers) - 1; i >= 0; i-- {
+		invoker, call := c.invokers[i], next
+		next = func() (any, error) {
+			return invoker(typeof, call)
+		}
+	}
+
+	return next()
 }
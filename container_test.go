@@ -1,6 +1,12 @@
 package goinject
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -14,6 +20,17 @@ type (
 	}
 )
 
+// TestConfig holds nested pointers and copies them deeply.
+type TestConfig struct {
+	Service *TestService
+	Tags    []string
+}
+
+func (c *TestConfig) DeepCopy() any {
+	service := *c.Service
+	return &TestConfig{Service: &service, Tags: slices.Clone(c.Tags)}
+}
+
 func TestContainer_Register(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -88,6 +105,53 @@ func TestContainer_RegisterFactory(t *testing.T) {
 	}
 }
 
+func TestContainer_RegisterFactoryErrorMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory interface{}
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "not a function",
+			factory: "not a function",
+			wantErr: ErrFactoryMustBeAFunction,
+			wantMsg: "factory must be a function, got string",
+		},
+		{
+			name:    "takes arguments",
+			factory: func(string, int) *TestService { return nil },
+			wantErr: ErrFactoryMustTakeNoArguments,
+			wantMsg: "factory must take no arguments, got func(string, int) *goinject.TestService",
+		},
+		{
+			name:    "returns two values",
+			factory: func() (*TestService, error) { return nil, nil },
+			wantErr: ErrFactoryMustReturnOneValue,
+			wantMsg: "factory must return one value, got func() (*goinject.TestService, error)",
+		},
+		{
+			name:    "returns non-pointer",
+			factory: func() TestService { return TestService{} },
+			wantErr: ErrOutputMustBeAPointer,
+			wantMsg: "output must be a pointer, got func() goinject.TestService",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().RegisterFactory(tt.factory)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RegisterFactory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && err.Error() != tt.wantMsg {
+				t.Errorf("RegisterFactory() message = %q, want %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
 func TestContainer_Get(t *testing.T) {
 	c := New()
 	service := &TestService{Name: "test"}
@@ -179,3 +243,638 @@ func TestMustGet(t *testing.T) {
 
 	_ = MustGet[AnotherService](c)
 }
+
+func TestContainer_RegisteredTypesOrdered(t *testing.T) {
+	type (
+		First  struct{}
+		Second struct{}
+		Third  struct{}
+	)
+
+	c := New()
+
+	if err := c.Register(&Third{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.RegisterFactory(func() *First { return &First{} }); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.Register(&Second{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	// Re-registering must keep the original position.
+	if err := c.Register(&Third{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	want := []reflect.Type{
+		reflect.TypeOf(&Third{}),
+		reflect.TypeOf(&First{}),
+		reflect.TypeOf(&Second{}),
+	}
+
+	if got := c.RegisteredTypesOrdered(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RegisteredTypesOrdered() got = %v, want %v", got, want)
+	}
+
+	if !c.Unregister((*First)(nil)) {
+		t.Fatal("Unregister() should report the removed registration")
+	}
+
+	want = []reflect.Type{
+		reflect.TypeOf(&Third{}),
+		reflect.TypeOf(&Second{}),
+	}
+
+	if got := c.RegisteredTypesOrdered(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RegisteredTypesOrdered() after Unregister got = %v, want %v", got, want)
+	}
+
+	sorted := []reflect.Type{
+		reflect.TypeOf(&Second{}),
+		reflect.TypeOf(&Third{}),
+	}
+
+	if got := c.RegisteredTypes(); !reflect.DeepEqual(got, sorted) {
+		t.Errorf("RegisteredTypes() got = %v, want %v", got, sorted)
+	}
+}
+
+func TestContainer_Unregister(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if !c.Unregister((*TestService)(nil)) {
+		t.Error("Unregister() got = false, want true")
+	}
+
+	if c.Unregister((*TestService)(nil)) {
+		t.Error("Unregister() of a missing type got = true, want false")
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestPickE(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	var picked *TestService
+	if err := PickE(c, func(s *TestService) { picked = s }); err != nil {
+		t.Errorf("PickE[T]() unexpected error = %v", err)
+	}
+
+	if picked != service {
+		t.Errorf("PickE[T]() got = %p, want %p", picked, service)
+	}
+
+	called := false
+	if err := PickE(c, func(*AnotherService) { called = true }); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("PickE[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if called {
+		t.Error("PickE[T]() should not call fn when service not found")
+	}
+}
+
+func TestPick(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	var picked *TestService
+	Pick(c, func(s *TestService) { picked = s })
+
+	if picked != service {
+		t.Errorf("Pick[T]() got = %p, want %p", picked, service)
+	}
+
+	// Test panic on missing service
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Pick[T]() should panic when service not found")
+		}
+	}()
+
+	Pick(c, func(*AnotherService) {})
+}
+
+func TestGenericGetValue_NotFound(t *testing.T) {
+	type User struct{ Name string }
+
+	var user User
+	err := GetValue(New(), &user)
+
+	if !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("GetValue[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if err == nil || !strings.Contains(err.Error(), "GetValue[goinject.User]") {
+		t.Errorf("GetValue[T]() error = %v, want it to name User", err)
+	}
+}
+
+func TestContainer_GetInterface(t *testing.T) {
+	c := New()
+	buffer := &bytes.Buffer{}
+
+	if err := c.Register(buffer); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	var writer io.Writer
+	result, err := c.Get(&writer)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+
+	if writer != io.Writer(buffer) {
+		t.Errorf("Get() assigned = %v, want %v", writer, buffer)
+	}
+
+	if result != any(buffer) {
+		t.Errorf("Get() got = %v, want %v", result, buffer)
+	}
+
+	var other io.Writer
+	if err := c.GetValue(&other); err != nil {
+		t.Fatalf("GetValue() unexpected error = %v", err)
+	}
+
+	if other != io.Writer(buffer) {
+		t.Errorf("GetValue() assigned = %v, want %v", other, buffer)
+	}
+
+	if err := c.Register(&strings.Builder{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if _, err := c.Get(&writer); !errors.Is(err, ErrAmbiguousResolution) {
+		t.Errorf("Get() error = %v, want %v", err, ErrAmbiguousResolution)
+	}
+}
+
+func TestContainer_TypesOfKind(t *testing.T) {
+	type (
+		Handler func() string
+		Events  chan string
+	)
+
+	c := New()
+	handler := Handler(func() string { return "handled" })
+	events := make(Events)
+
+	for _, service := range []any{&TestService{}, &handler, &events, &AnotherService{}} {
+		if err := c.Register(service); err != nil {
+			t.Fatalf("failed to register service: %v", err)
+		}
+	}
+
+	built := false
+	if err := c.RegisterFactory(func() *Handler {
+		built = true
+		return &handler
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	structs := []reflect.Type{reflect.TypeOf(&TestService{}), reflect.TypeOf(&AnotherService{})}
+	if got := c.TypesOfKind(reflect.Struct); !reflect.DeepEqual(got, structs) {
+		t.Errorf("TypesOfKind(Struct) got = %v, want %v", got, structs)
+	}
+
+	funcs := []reflect.Type{reflect.TypeOf(&handler)}
+	if got := c.TypesOfKind(reflect.Func); !reflect.DeepEqual(got, funcs) {
+		t.Errorf("TypesOfKind(Func) got = %v, want %v", got, funcs)
+	}
+
+	chans := []reflect.Type{reflect.TypeOf(&events)}
+	if got := c.TypesOfKind(reflect.Chan); !reflect.DeepEqual(got, chans) {
+		t.Errorf("TypesOfKind(Chan) got = %v, want %v", got, chans)
+	}
+
+	if built {
+		t.Error("TypesOfKind() should not resolve anything")
+	}
+}
+
+func TestGetOk(t *testing.T) {
+	errBroken := errors.New("broken")
+	c := New()
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func() (*AnotherService, error) {
+		return nil, errBroken
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	if err := c.Provide(func(*AnotherService) *TestRepository {
+		return &TestRepository{}
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	if err := c.Provide(func(*TestRepository) *TestHandler {
+		return &TestHandler{}
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	result, ok, err := GetOk[TestService](c)
+	if result != service || !ok || err != nil {
+		t.Errorf("GetOk[present]() got = %v, %v, %v, want %v, true, nil", result, ok, err, service)
+	}
+
+	type Missing struct{}
+	if result, ok, err := GetOk[Missing](c); result != nil || ok || err != nil {
+		t.Errorf("GetOk[missing]() got = %v, %v, %v, want nil, false, nil", result, ok, err)
+	}
+
+	if result, ok, err := GetOk[AnotherService](c); result != nil || !ok || !errors.Is(err, errBroken) {
+		t.Errorf("GetOk[failing]() got = %v, %v, %v, want nil, true, %v", result, ok, err, errBroken)
+	}
+
+	// A dependency failing deeper in the graph is still a failure, not an absence.
+	if result, ok, err := GetOk[TestHandler](c); result != nil || !ok || !errors.Is(err, errBroken) {
+		t.Errorf("GetOk[failing dependency]() got = %v, %v, %v, want nil, true, %v", result, ok, err, errBroken)
+	}
+}
+
+func TestGetOrNil(t *testing.T) {
+	errBroken := errors.New("broken")
+	c := New()
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func() (*AnotherService, error) {
+		return nil, errBroken
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	if result := GetOrNil[TestService](c); result != service {
+		t.Errorf("GetOrNil[present]() got = %v, want %v", result, service)
+	}
+
+	type Missing struct{}
+	if result := GetOrNil[Missing](c); result != nil {
+		t.Errorf("GetOrNil[missing]() got = %v, want nil", result)
+	}
+
+	defer func() {
+		if p, ok := recover().(*MustPanic); !ok || p.Op != "GetOrNil" || !errors.Is(p, errBroken) {
+			t.Errorf("GetOrNil[failing]() panicked with %v, want a *MustPanic wrapping %v", p, errBroken)
+		}
+	}()
+
+	GetOrNil[AnotherService](c)
+	t.Error("GetOrNil[failing]() should panic")
+}
+
+func TestGetPtrOrNil(t *testing.T) {
+	errBroken := errors.New("broken")
+	c := New()
+
+	if err := c.Provide(func() *TestService {
+		return &TestService{Name: "shared"}
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	if err := c.Provide(func() (*AnotherService, error) {
+		return nil, errBroken
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	shared := GetPtrOrNil[TestService](c)
+	if shared == nil || shared != MustGet[TestService](c) {
+		t.Fatalf("GetPtrOrNil[present]() got = %p, want the shared instance %p", shared, MustGet[TestService](c))
+	}
+
+	shared.Name = "changed"
+	if got := MustGet[TestService](c).Name; got != "changed" {
+		t.Errorf("change through GetPtrOrNil() not visible, got Name = %q", got)
+	}
+
+	type Missing struct{}
+	if result := GetPtrOrNil[Missing](c); result != nil {
+		t.Errorf("GetPtrOrNil[missing]() got = %v, want nil", result)
+	}
+
+	defer func() {
+		if p, ok := recover().(*MustPanic); !ok || p.Op != "GetPtrOrNil" || !errors.Is(p, errBroken) {
+			t.Errorf("GetPtrOrNil[failing]() panicked with %v, want a *MustPanic wrapping %v", p, errBroken)
+		}
+	}()
+
+	GetPtrOrNil[AnotherService](c)
+	t.Error("GetPtrOrNil[failing]() should panic")
+}
+
+func TestContainer_GetOrElse(t *testing.T) {
+	c := New()
+	registered := &TestService{Name: "registered"}
+
+	if err := c.Register(registered); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	fallback := func(reflect.Type) (any, error) {
+		return &AnotherService{ID: 7}, nil
+	}
+
+	result, err := c.GetOrElse(&TestService{}, fallback)
+	if err != nil || result != registered {
+		t.Errorf("GetOrElse() got = %v, %v, want %v", result, err, registered)
+	}
+
+	result, err = c.GetOrElse(&AnotherService{}, fallback)
+	if err != nil {
+		t.Fatalf("GetOrElse() unexpected error = %v", err)
+	}
+
+	if result.(*AnotherService).ID != 7 {
+		t.Errorf("GetOrElse() got = %v, want %v", result, 7)
+	}
+
+	// The fallback result is not registered.
+	if _, err := Get[AnotherService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	errUnavailable := errors.New("unavailable")
+
+	if _, err := c.GetOrElse(&AnotherService{}, func(reflect.Type) (any, error) {
+		return nil, errUnavailable
+	}); !errors.Is(err, errUnavailable) {
+		t.Errorf("GetOrElse() error = %v, want %v", err, errUnavailable)
+	}
+
+	if _, err := c.GetOrElse(&AnotherService{}, func(reflect.Type) (any, error) {
+		return &TestService{}, nil
+	}); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("GetOrElse() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+}
+
+func TestContainer_EnsureDefault(t *testing.T) {
+	newDefault := func() *TestService { return &TestService{Name: "default"} }
+
+	tests := []struct {
+		name     string
+		register func(c *Container) error
+		want     string
+	}{
+		{"absent", func(*Container) error { return nil }, "default"},
+		{"registered by the app", func(c *Container) error { return c.Register(&TestService{Name: "app"}) }, "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+
+			if err := tt.register(c); err != nil {
+				t.Fatalf("failed to register service: %v", err)
+			}
+
+			if err := c.EnsureDefault(newDefault); err != nil {
+				t.Fatalf("EnsureDefault() unexpected error = %v", err)
+			}
+
+			result, err := Get[TestService](c)
+			if err != nil || result.Name != tt.want {
+				t.Errorf("Get[T]() got = %v, %v, want %v", result, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustPanic(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	c := New()
+	c.Freeze()
+
+	tests := []struct {
+		name     string
+		must     func()
+		wantOp   string
+		wantType reflect.Type
+		wantErr  error
+	}{
+		{"MustGet", func() { MustGet[TestService](c) }, "MustGet", reflect.TypeFor[*TestService](), ErrServiceNotFound},
+		{"MustRegister", func() { MustRegister(c, &TestService{}) }, "MustRegister", reflect.TypeFor[*TestService](), ErrContainerFrozen},
+		{"MustInvoke", func() { MustInvoke(c, func() error { return errBoom }) }, "MustInvoke", reflect.TypeFor[func() error](), errBoom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				p, ok := recover().(*MustPanic)
+				if !ok {
+					t.Fatalf("%s should panic with a *MustPanic", tt.name)
+				}
+
+				if p.Op != tt.wantOp || p.Type != tt.wantType || !errors.Is(p.Err, tt.wantErr) {
+					t.Errorf("%s panicked with %v, want op %q, type %v and error %v", tt.name, p, tt.wantOp, tt.wantType, tt.wantErr)
+				}
+			}()
+
+			tt.must()
+		})
+	}
+}
+
+func TestContainer_DefinedTypes(t *testing.T) {
+	type Celsius float64
+
+	c := New()
+
+	celsius, reading := Celsius(21.5), 70.7
+	if err := c.Register(&celsius); err != nil {
+		t.Fatalf("failed to register Celsius: %v", err)
+	}
+	if err := c.Register(&reading); err != nil {
+		t.Fatalf("failed to register float64: %v", err)
+	}
+
+	gotCelsius, err := Get[Celsius](c)
+	if err != nil || *gotCelsius != celsius {
+		t.Errorf("Get[Celsius]() got = %v, %v, want %v", gotCelsius, err, celsius)
+	}
+
+	gotReading, err := Get[float64](c)
+	if err != nil || *gotReading != reading {
+		t.Errorf("Get[float64]() got = %v, %v, want %v", gotReading, err, reading)
+	}
+
+	if got := len(c.RegisteredTypes()); got != 2 {
+		t.Errorf("RegisteredTypes() got %d types, want 2", got)
+	}
+}
+
+func TestContainer_GetMany(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+	store := &TestStore{}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("failed to register reader: %v", err)
+	}
+
+	var (
+		shared  *TestService
+		missing *AnotherService
+		reader  TestReader
+		copied  TestService
+	)
+
+	errs := c.GetMany(&shared, &missing, &reader, &copied, TestService{})
+
+	wantErrs := []error{nil, ErrServiceNotFound, nil, nil, ErrOutputMustBeAPointer}
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("GetMany() got %d errors, want %d", len(errs), len(wantErrs))
+	}
+
+	for i, want := range wantErrs {
+		if !errors.Is(errs[i], want) || want == nil && errs[i] != nil {
+			t.Errorf("GetMany() error %d = %v, want %v", i, errs[i], want)
+		}
+	}
+
+	if shared != service || missing != nil || reader != TestReader(store) || copied.Name != service.Name {
+		t.Errorf("GetMany() filled %v, %v, %v and %v", shared, missing, reader, copied)
+	}
+}
+
+func TestContainer_GetValueDeepCopier(t *testing.T) {
+	c := New()
+	config := &TestConfig{Service: &TestService{Name: "test"}, Tags: []string{"a"}}
+	repository := &TestRepository{Service: &TestService{Name: "test"}}
+
+	if err := c.Register(config); err != nil {
+		t.Fatalf("failed to register config: %v", err)
+	}
+
+	if err := c.Register(repository); err != nil {
+		t.Fatalf("failed to register repository: %v", err)
+	}
+
+	var copied TestConfig
+	if err := c.GetValue(&copied); err != nil {
+		t.Fatalf("GetValue() unexpected error = %v", err)
+	}
+
+	if !reflect.DeepEqual(&copied, config) {
+		t.Errorf("GetValue() got = %+v, want a copy of %+v", copied, config)
+	}
+
+	if copied.Service == config.Service || &copied.Tags[0] == &config.Tags[0] {
+		t.Error("GetValue() of a DeepCopier should share no pointer state with the singleton")
+	}
+
+	// Without DeepCopy the copy stays shallow.
+	var shallow TestRepository
+	if err := c.GetValue(&shallow); err != nil {
+		t.Fatalf("GetValue() unexpected error = %v", err)
+	}
+
+	if shallow.Service != repository.Service {
+		t.Error("GetValue() of other services should copy shallowly")
+	}
+}
+
+func TestGet_TypeToken(t *testing.T) {
+	c := New()
+	store := &TestStore{data: "cached"}
+
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("RegisterAs[TestReader]() unexpected error = %v", err)
+	}
+
+	// The second round is served from the keys cache.
+	for range 2 {
+		concrete, err := Get[TestStore](c)
+		if err != nil || concrete != store {
+			t.Fatalf("Get[TestStore]() got = %p, %v, want %p", concrete, err, store)
+		}
+
+		reader, err := Get[TestReader](c)
+		if err != nil || *reader != TestReader(store) {
+			t.Fatalf("Get[TestReader]() got = %v, %v, want %p", reader, err, store)
+		}
+	}
+
+	if got, want := keyFor[TestReader](), reflect.TypeFor[TestReader](); got != want {
+		t.Errorf("keyFor[TestReader]() got = %v, want %v", got, want)
+	}
+
+	if got, want := keyFor[TestStore](), reflect.TypeFor[*TestStore](); got != want {
+		t.Errorf("keyFor[TestStore]() got = %v, want %v", got, want)
+	}
+
+	if _, err := Get[TestService](c); err != ErrServiceNotFound {
+		t.Errorf("Get[TestService]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func BenchmarkGet_Generic(b *testing.B) {
+	c := New()
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		b.Fatalf("failed to register service: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := Get[TestService](c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGet_OutValue(b *testing.B) {
+	c := New()
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		b.Fatalf("failed to register service: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		// What Get[T] did before the keys cache.
+		var out TestService
+		if _, err := c.Get(&out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
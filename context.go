@@ -0,0 +1,139 @@
+package goinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// GetContext retrieves a dependency like Get, passing ctx to every constructor
+// resolved on the way that takes a context.Context parameter.
+// Once ctx is done no further factory runs and its error is returned.
+//
+// Example:
+//
+//	container.Provide(func(ctx context.Context) (*DB, error) {
+//	    return Connect(ctx, dsn)
+//	})
+//
+//	var db DB
+//	_, err := container.GetContext(ctx, &db)
+func (c *Container) GetContext(ctx context.Context, out any) (any, error) {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := c.resolveOut(ctx, typeof)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// An interface is filled through the out pointer, there is no other way to hand it back typed.
+	if typeof.Kind() == reflect.Interface {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(service))
+	}
+
+	return service, nil
+}
+
+// GetWithTimeout retrieves a dependency like GetContext, bounding the whole resolution,
+// nested constructors included, to d. Once d has passed no further factory runs and
+// ErrResolutionTimeout is returned, also when a single constructor overran d. A constructor
+// cannot be interrupted, so an instance it finishes late is still cached as usual and
+// returned by the next resolution without building it again.
+//
+// Example:
+//
+//	var repo UserRepository
+//	_, err := container.GetWithTimeout(&repo, 2*time.Second)
+//	if errors.Is(err, goinject.ErrResolutionTimeout) {
+//	    log.Fatal("startup is too slow")
+//	}
+func (c *Container) GetWithTimeout(out any, d time.Duration) (any, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	service, err := c.GetContext(ctx, out)
+	{
+		// A constructor that overran the deadline still returns its instance.
+		if err == nil {
+			err = ctx.Err()
+		}
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s: %w", ErrResolutionTimeout, d, err)
+		}
+	}
+
+	return service, err
+}
+
+// Invoke calls fn with its arguments resolved from the container.
+// The function must return nothing or an error, which Invoke returns.
+//
+// Example:
+//
+//	err := container.Invoke(func(db *DB, logger *Logger) error {
+//	    return db.Migrate(logger)
+//	})
+func (c *Container) Invoke(fn any) error {
+	return c.InvokeContext(context.Background(), fn)
+}
+
+// InvokeContext calls fn like Invoke, resolving its arguments with GetContext semantics.
+// A context.Context parameter of fn receives ctx.
+//
+// Example:
+//
+//	err := container.InvokeContext(ctx, func(ctx context.Context, db *DB) error {
+//	    return db.Ping(ctx)
+//	})
+func (c *Container) InvokeContext(ctx context.Context, fn any) error {
+
+	fnValue := reflect.ValueOf(fn)
+
+	fnType := fnValue.Type()
+	{
+		if fnType.Kind() != reflect.Func {
+			return fmt.Errorf("%w, got %s", ErrFactoryMustBeAFunction, c.name(fnType))
+		}
+
+		if n := fnType.NumOut(); n > 1 || n == 1 && fnType.Out(0) != errorType {
+			return fmt.Errorf("%w, got %s", ErrInvokeMustReturnError, c.name(fnType))
+		}
+	}
+
+	deps := make([]reflect.Type, fnType.NumIn())
+	for i := range deps {
+		deps[i] = fnType.In(i)
+	}
+
+	args, err := c.arguments(ctx, fnType, deps, fnType.IsVariadic())
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	var out []reflect.Value
+	if fnType.IsVariadic() {
+		out = fnValue.CallSlice(args)
+	} else {
+		out = fnValue.Call(args)
+	}
+
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+
+	return nil
+}
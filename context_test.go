@@ -0,0 +1,175 @@
+package goinject
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testContextKey struct{}
+
+func TestContainer_GetContext(t *testing.T) {
+	c := New()
+	var received []context.Context
+
+	if err := c.Provide(func(ctx context.Context) *TestService {
+		received = append(received, ctx)
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func(ctx context.Context, service *TestService) *TestRepository {
+		received = append(received, ctx)
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "request")
+
+	if _, err := c.GetContext(ctx, &TestRepository{}); err != nil {
+		t.Fatalf("GetContext() unexpected error = %v", err)
+	}
+
+	if len(received) != 2 || received[0] != ctx || received[1] != ctx {
+		t.Errorf("factories received %v, want %v twice", received, ctx)
+	}
+}
+
+func TestContainer_GetContext_Cancelled(t *testing.T) {
+	c := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	built := false
+
+	// The inner factory cancels the resolution, the outer one must not run.
+	if err := c.Provide(func(context.Context) *TestService {
+		cancel()
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func(ctx context.Context, service *TestService) *TestRepository {
+		built = true
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if _, err := c.GetContext(ctx, &TestRepository{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	if built {
+		t.Error("factory should not run once the resolution is cancelled")
+	}
+
+	// An already cancelled context stops before any factory runs.
+	if _, err := c.GetContext(ctx, &TestRepository{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestContainer_InvokeContext(t *testing.T) {
+	c := New()
+
+	if err := c.Provide(func(ctx context.Context) *TestService {
+		return &TestService{Name: ctx.Value(testContextKey{}).(string)}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "request")
+
+	var got context.Context
+	var name string
+
+	if err := c.InvokeContext(ctx, func(ctx context.Context, service *TestService) {
+		got, name = ctx, service.Name
+	}); err != nil {
+		t.Fatalf("InvokeContext() unexpected error = %v", err)
+	}
+
+	if got != ctx || name != "request" {
+		t.Errorf("InvokeContext() got = %v, %v, want %v, %v", got, name, ctx, "request")
+	}
+
+	errFailed := errors.New("failed")
+	if err := c.Invoke(func(*TestService) error { return errFailed }); !errors.Is(err, errFailed) {
+		t.Errorf("Invoke() error = %v, want %v", err, errFailed)
+	}
+
+	if err := c.Invoke(func() int { return 0 }); !errors.Is(err, ErrInvokeMustReturnError) {
+		t.Errorf("Invoke() error = %v, want %v", err, ErrInvokeMustReturnError)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := c.InvokeContext(cancelled, func(*TestService) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("InvokeContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestContainer_GetWithTimeout_SlowFactory(t *testing.T) {
+	c := New()
+	calls := 0
+
+	if err := c.Provide(func() *TestService {
+		calls++
+		time.Sleep(100 * time.Millisecond)
+		return &TestService{Name: "late"}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if service, err := c.GetWithTimeout(&TestService{}, 20*time.Millisecond); !errors.Is(err, ErrResolutionTimeout) {
+		t.Errorf("GetWithTimeout() got = %v, %v, want %v", service, err, ErrResolutionTimeout)
+	}
+
+	// The late instance is cached.
+	if service, err := Get[TestService](c); err != nil || service.Name != "late" || calls != 1 {
+		t.Errorf("Get[T]() got = %v, %v after %d calls, want the late instance built once", service, err, calls)
+	}
+}
+
+func TestContainer_GetWithTimeout(t *testing.T) {
+	c := New()
+	built := false
+
+	// Each constructor is fast enough on its own, together they are not.
+	if err := c.Provide(func() *TestService {
+		time.Sleep(30 * time.Millisecond)
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		time.Sleep(30 * time.Millisecond)
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func(repository *TestRepository) *TestHandler {
+		built = true
+		return &TestHandler{Repository: repository}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if _, err := c.GetWithTimeout(&TestHandler{}, 40*time.Millisecond); !errors.Is(err, ErrResolutionTimeout) {
+		t.Errorf("GetWithTimeout() error = %v, want %v", err, ErrResolutionTimeout)
+	}
+
+	if built {
+		t.Error("factory should not run once the resolution timed out")
+	}
+
+	if _, err := c.GetWithTimeout(&TestHandler{}, time.Second); err != nil {
+		t.Errorf("GetWithTimeout() unexpected error = %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package goinject
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// eventBuffer is the capacity of the channel returned by Events.
+const eventBuffer = 256
+
+// ResolveEvent describes one resolution, see Events.
+type ResolveEvent struct {
+	Type     reflect.Type
+	Duration time.Duration
+	// Built reports whether a factory ran for the type itself, rather than returning
+	// a registered or cached instance.
+	Built bool
+	Err   error
+}
+
+// events is the resolution event stream of a container, created by the first call to Events.
+type events struct {
+	active atomic.Bool
+	mu     sync.RWMutex
+	ch     chan ResolveEvent
+	closed bool
+}
+
+// Events returns a channel that receives an event for every resolution from now on,
+// including the resolutions of dependencies. The channel is buffered and events are
+// dropped while it is full, so a slow observer never blocks resolution. Every call
+// returns the same channel; it is closed by Close.
+//
+// Example:
+//
+//	go func() {
+//	    for event := range container.Events() {
+//	        log.Printf("resolved %s in %s (built: %v)", event.Type, event.Duration, event.Built)
+//	    }
+//	}()
+func (c *Container) Events() <-chan ResolveEvent {
+
+	c.events.mu.Lock()
+	defer c.events.mu.Unlock()
+
+	if c.events.ch == nil {
+		c.events.ch = make(chan ResolveEvent, eventBuffer)
+
+		if c.events.closed {
+			close(c.events.ch)
+		} else {
+			c.events.active.Store(true)
+		}
+	}
+
+	return c.events.ch
+}
+
+// emit sends the event unless the channel is full or closed.
+func (e *events) emit(event ResolveEvent) {
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return
+	}
+
+	select {
+	case e.ch <- event:
+	default:
+	}
+}
+
+// close closes the channel, after which no event is sent anymore.
+func (e *events) close() {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return
+	}
+
+	e.closed = true
+	e.active.Store(false)
+
+	if e.ch != nil {
+		close(e.ch)
+	}
+}
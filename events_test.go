@@ -0,0 +1,96 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestContainer_Events(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func(s *TestService) *TestRepository { return &TestRepository{Service: s} }); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	events := c.Events()
+
+	if c.Events() != events {
+		t.Error("Events() should return the same channel on every call")
+	}
+
+	for range 2 {
+		if _, err := Get[TestRepository](c); err != nil {
+			t.Fatalf("Get[T]() unexpected error = %v", err)
+		}
+	}
+
+	if _, err := Get[AnotherService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	type event struct {
+		typeof reflect.Type
+		built  bool
+		err    error
+	}
+
+	want := []event{
+		{reflect.TypeFor[*TestService](), false, nil},
+		{reflect.TypeFor[*TestRepository](), true, nil},
+		{reflect.TypeFor[*TestRepository](), false, nil},
+		{reflect.TypeFor[*AnotherService](), false, ErrServiceNotFound},
+	}
+
+	var got []event
+	for e := range events {
+		got = append(got, event{e.Type, e.Built, e.Err})
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Events() got %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i].typeof != want[i].typeof || got[i].built != want[i].built || !errors.Is(got[i].err, want[i].err) {
+			t.Errorf("event %d got = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestContainer_EventsDropWhenFull(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	events := c.Events()
+
+	for range eventBuffer + 10 {
+		if _, err := Get[TestService](c); err != nil {
+			t.Fatalf("Get[T]() unexpected error = %v", err)
+		}
+	}
+
+	if got := len(events); got != eventBuffer {
+		t.Errorf("Events() buffered %d events, want %d", got, eventBuffer)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if _, ok := <-c.Events(); !ok {
+		t.Error("Close() should keep buffered events readable")
+	}
+}
@@ -0,0 +1,255 @@
+package goinject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Lifetime describes how instances built by a factory are shared.
+type Lifetime int
+
+const (
+	// Singleton instances are built once, on first resolution, and shared afterwards.
+	Singleton Lifetime = iota
+	// Cached instances are shared like singletons but may be evicted, see RegisterCached.
+	Cached
+	// Transient instances are built anew on every resolution, see RegisterTransient.
+	Transient
+	// Scoped instances are shared within the scope resolving them, see RegisterScoped.
+	Scoped
+	// GoroutineLocal instances are shared within the goroutine resolving them, see RegisterGoroutineLocal.
+	GoroutineLocal
+)
+
+// String returns the name of the lifetime.
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Cached:
+		return "cached"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	case GoroutineLocal:
+		return "goroutine-local"
+	default:
+		return "Lifetime(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// factory is a registered constructor together with its cached singleton instance.
+type factory struct {
+	concrete typeof
+	call     func(ctx context.Context, c *Container) (any, error)
+	deps     []typeof
+	variadic bool
+	lifetime Lifetime
+	mu       sync.Mutex
+	owner    atomic.Uint64
+	instance atomic.Pointer[any]
+	locals   sync.Map // goroutine id -> instance, for goroutine-local factories
+	named    bool     // builds a named instance, which is not materialized under its type
+	running  sync.Map // goroutine id -> struct{}, for the factories that do not cache
+}
+
+// newFactory wraps a constructor of the concrete type into a factory entry.
+// The constructor receives the container resolving it, which is the scope for scoped factories.
+func newFactory(concrete typeof, call func(ctx context.Context, c *Container) (any, error)) *factory {
+	return &factory{concrete: concrete, call: call}
+}
+
+// eager reports whether Build constructs the factory up front: only singleton and cached
+// instances are shared by the whole container, the others are built per resolution,
+// scope or goroutine.
+func (f *factory) eager() bool {
+	return f.lifetime == Singleton || f.lifetime == Cached
+}
+
+// fresh returns a copy of the factory that has not built anything yet.
+func (f *factory) fresh() *factory {
+	return &factory{concrete: f.concrete, call: f.call, deps: f.deps, variadic: f.variadic, lifetime: f.lifetime, named: f.named}
+}
+
+// cached returns the instance built by the factory, if there is one.
+func (f *factory) cached() (any, bool) {
+
+	if instance := f.instance.Load(); instance != nil {
+		return *instance, true
+	}
+
+	return nil, false
+}
+
+// run calls the factory through the invokers, unless the resolution is already cancelled.
+func (c *Container) run(ctx context.Context, typeof typeof, f *factory) (any, error) {
+
+	// A cancelled resolution stops before the next factory runs.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	measured := c.measured()
+
+	var start time.Time
+	if measured {
+		start = time.Now()
+	}
+
+	instance, err := c.invoke(ctx, typeof, f.call)
+	{
+		if measured {
+			c.metrics.of(typeof).observe(time.Since(start))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// An invoker may short-circuit with an instance of its own.
+		if len(c.invokers) > 0 {
+			if err := c.conforms(typeof, instance, "invoker"); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.rejectNil && isNil(instance) {
+			return nil, fmt.Errorf("%w: %s", ErrNilFactoryResult, c.name(typeof))
+		}
+	}
+
+	for _, hook := range c.postResolve {
+		instance = hook(typeof, instance)
+
+		if err := c.conforms(typeof, instance, "post-resolve hook"); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+// conforms checks that what source produced for the type can be used as one.
+// A nil instance is left to WithRejectNilFactoryResults.
+func (c *Container) conforms(typeof typeof, instance any, source string) error {
+
+	if instance != nil && !reflect.TypeOf(instance).AssignableTo(typeof) {
+		return fmt.Errorf("%w: %s returned %s for %s", ErrInstanceType, source, c.name(reflect.TypeOf(instance)), c.name(typeof))
+	}
+
+	return nil
+}
+
+// isNil reports whether the instance is nil or a nil pointer.
+func isNil(instance any) bool {
+
+	if instance == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(instance)
+
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+// build returns the cached instance, constructing it on first use.
+// Construction is serialized per factory; a goroutine that re-enters the
+// factory it is currently constructing gets ErrReentrantResolution instead of
+// deadlocking on the factory lock, or ErrCircularDependency for the transient and
+// goroutine-local factories, which are not serialized, see guard.
+func (c *Container) build(ctx context.Context, typeof typeof, f *factory) (any, error) {
+
+	if f.lifetime == Transient {
+		return c.guard(typeof, f, func() (any, error) {
+			return c.run(ctx, typeof, f)
+		})
+	}
+
+	if f.lifetime == GoroutineLocal {
+		return c.guard(typeof, f, func() (any, error) {
+			return c.local(ctx, typeof, f)
+		})
+	}
+
+	if instance, ok := f.cached(); ok {
+		if f.lifetime == Cached {
+			c.touch(typeof, f, false)
+		}
+
+		return instance, nil
+	}
+
+	gid := goroutineID()
+	{
+		if f.owner.Load() == gid {
+			return nil, fmt.Errorf("%w: %s is already being resolved", ErrReentrantResolution, c.name(typeof))
+		}
+	}
+
+	instance, built, err := c.construct(ctx, typeof, f, gid)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Eviction locks other factories, so it must happen once this one is unlocked.
+	if f.lifetime == Cached {
+		c.touch(typeof, f, built)
+	}
+
+	return instance, nil
+}
+
+// guard runs construct for a factory that does not cache its instance, which the owner check
+// of build cannot protect: a goroutine resolving the type again while constructing it would
+// recurse until the stack overflows, so it gets ErrCircularDependency instead.
+func (c *Container) guard(typeof typeof, f *factory, construct func() (any, error)) (any, error) {
+
+	gid := goroutineID()
+
+	if _, busy := f.running.LoadOrStore(gid, struct{}{}); busy {
+		return nil, fmt.Errorf("%w: %s resolves itself", ErrCircularDependency, c.name(typeof))
+	}
+	defer f.running.Delete(gid)
+
+	return construct()
+}
+
+// construct runs the factory under its lock unless another goroutine built it meanwhile.
+// It reports whether this call built the instance.
+func (c *Container) construct(ctx context.Context, typeof typeof, f *factory, gid uint64) (any, bool, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if instance, ok := f.cached(); ok {
+		return instance, false, nil
+	}
+
+	f.owner.Store(gid)
+	defer f.owner.Store(0)
+
+	instance, err := c.run(ctx, typeof, f)
+	{
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	f.instance.Store(&instance)
+
+	if !f.named {
+		c.mu.Lock()
+		c.materialize(typeof)
+		c.mu.Unlock()
+	}
+
+	return instance, true, nil
+}
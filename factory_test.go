@@ -0,0 +1,130 @@
+package goinject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFactory_ReentrantResolution(t *testing.T) {
+	c := New()
+
+	var inner error
+	if err := c.RegisterFactory(func() *TestService {
+		_, inner = Get[TestService](c)
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if !errors.Is(inner, ErrReentrantResolution) {
+		t.Errorf("nested Get[T]() error = %v, want %v", inner, ErrReentrantResolution)
+	}
+}
+
+func TestFactory_TransientCycle(t *testing.T) {
+	t.Run("self", func(t *testing.T) {
+		c := New()
+
+		var inner error
+		if err := c.RegisterTransient(func() *TestService {
+			_, inner = Get[TestService](c)
+			return &TestService{}
+		}); err != nil {
+			t.Fatalf("failed to register factory: %v", err)
+		}
+
+		if _, err := Get[TestService](c); err != nil {
+			t.Fatalf("Get[T]() unexpected error = %v", err)
+		}
+
+		if !errors.Is(inner, ErrCircularDependency) {
+			t.Errorf("nested Get[T]() error = %v, want %v", inner, ErrCircularDependency)
+		}
+	})
+
+	t.Run("through a per-requester constructor", func(t *testing.T) {
+		c := New()
+
+		if err := c.Provide(func(reflect.Type, *TestRepository) *TestService {
+			return &TestService{}
+		}); err != nil {
+			t.Fatalf("failed to provide service: %v", err)
+		}
+
+		if err := c.Provide(func(reflect.Type, *TestService) *TestRepository {
+			return &TestRepository{}
+		}); err != nil {
+			t.Fatalf("failed to provide repository: %v", err)
+		}
+
+		if _, err := Get[TestService](c); !errors.Is(err, ErrCircularDependency) {
+			t.Errorf("Get[T]() error = %v, want %v", err, ErrCircularDependency)
+		}
+	})
+}
+
+func TestContainer_SwapFactory(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "v0"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	held, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			name := fmt.Sprintf("v%d", i+1)
+			if err := c.SwapFactory(func() *TestService {
+				return &TestService{Name: name}
+			}); err != nil {
+				t.Errorf("SwapFactory() unexpected error = %v", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if result, err := Get[TestService](c); err != nil || result.Name == "" {
+				t.Errorf("Get[T]() got = %v, %v", result, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := c.SwapFactory(func() *TestService {
+		return &TestService{Name: "final"}
+	}); err != nil {
+		t.Fatalf("SwapFactory() unexpected error = %v", err)
+	}
+
+	result, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if result.Name != "final" {
+		t.Errorf("Get[T]() after SwapFactory got = %v, want %v", result.Name, "final")
+	}
+
+	if held.Name != "v0" {
+		t.Errorf("previously resolved instance got = %v, want %v", held.Name, "v0")
+	}
+}
@@ -0,0 +1,107 @@
+package goinject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// GetOption changes how a single GetOpts call resolves its service.
+type GetOption func(*getOptions)
+
+// getOptions is the set of GetOption applied to a GetOpts call.
+type getOptions struct {
+	noCache    bool
+	onlyCached bool
+}
+
+// NoCache makes GetOpts run the factory of the service instead of returning its cached
+// instance. The fresh instance is not cached; dependencies are resolved as usual.
+// Registered instances are returned as they are.
+func NoCache() GetOption {
+	return func(o *getOptions) {
+		o.noCache = true
+	}
+}
+
+// OnlyCached makes GetOpts return ErrNotMaterialized instead of running a factory
+// whose instance does not exist yet.
+func OnlyCached() GetOption {
+	return func(o *getOptions) {
+		o.onlyCached = true
+	}
+}
+
+// GetOpts retrieves a dependency like Get, with the given options applied to the service itself.
+//
+// Example:
+//
+//	// a fresh connection, the shared one stays untouched
+//	_, err := container.GetOpts(&conn, goinject.NoCache())
+//
+//	// only if something already built it
+//	_, err = container.GetOpts(&cache, goinject.OnlyCached())
+func (c *Container) GetOpts(out any, opts ...GetOption) (any, error) {
+
+	var o getOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := c.resolveWith(typeof, o)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if typeof.Kind() == reflect.Interface {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(service))
+	}
+
+	return service, nil
+}
+
+// resolveWith resolves the type, following its bindings, with the options applied.
+func (c *Container) resolveWith(typeof typeof, o getOptions) (any, error) {
+
+	if !o.noCache && !o.onlyCached {
+		return c.resolve(context.Background(), typeof)
+	}
+
+	c.mu.RLock()
+	service, ok := c.providers[typeof]
+	factory := c.factories[typeof]
+	target := c.bindings[typeof]
+	c.mu.RUnlock()
+
+	if c.closed.Load() {
+		return nil, ErrContainerClosed
+	}
+
+	switch {
+	case ok:
+		return service, nil
+	case factory == nil && target != nil:
+		return c.resolveWith(target, o)
+	case factory == nil && o.onlyCached:
+		return nil, ErrServiceNotFound
+	case factory == nil:
+		return c.resolve(context.Background(), typeof)
+	case o.onlyCached:
+		if instance, ok := factory.cached(); ok {
+			return instance, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrNotMaterialized, c.name(typeof))
+	default:
+		return c.run(context.Background(), typeof, factory)
+	}
+}
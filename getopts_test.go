@@ -0,0 +1,49 @@
+package goinject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestContainer_GetOpts(t *testing.T) {
+	c := New()
+	builds := 0
+
+	if err := c.RegisterFactory(func() *TestService {
+		builds++
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if _, err := c.GetOpts(&TestService{}, OnlyCached()); !errors.Is(err, ErrNotMaterialized) {
+		t.Errorf("GetOpts(OnlyCached) error = %v, want %v", err, ErrNotMaterialized)
+	}
+
+	if builds != 0 {
+		t.Errorf("GetOpts(OnlyCached) ran the factory %d times, want 0", builds)
+	}
+
+	shared, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	fresh, err := c.GetOpts(&TestService{}, NoCache())
+	if err != nil {
+		t.Fatalf("GetOpts(NoCache) unexpected error = %v", err)
+	}
+
+	if fresh == shared || builds != 2 {
+		t.Errorf("GetOpts(NoCache) got = %p after %d builds, want a fresh instance", fresh, builds)
+	}
+
+	cached, err := c.GetOpts(&TestService{}, OnlyCached())
+	if err != nil || cached != shared {
+		t.Errorf("GetOpts(OnlyCached) got = %p, %v, want %p", cached, err, shared)
+	}
+
+	if _, err := c.GetOpts(&AnotherService{}, OnlyCached()); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("GetOpts(OnlyCached) error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
@@ -0,0 +1,48 @@
+// Package goinjecttest provides test helpers for containers built with goinject.
+// It is kept apart from goinject so that programs importing the container do not link testing.
+package goinjecttest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	goinject "github.com/fobus1289/goInject"
+)
+
+// AssertComplete fails the test if any registration of the container has a dependency
+// that cannot be satisfied, listing every one of them. It only plans the resolutions,
+// like Plan, so no factory runs.
+//
+// Example:
+//
+//	func TestWiring(t *testing.T) {
+//	    goinjecttest.AssertComplete(t, app.NewContainer())
+//	}
+func AssertComplete(t testing.TB, c *goinject.Container) {
+
+	t.Helper()
+
+	var problems []string
+	for _, typeof := range c.RegisteredTypesOrdered() {
+		if _, err := c.Plan(out(typeof)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", typeof, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		t.Errorf("container has unsatisfiable registrations:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+}
+
+// out returns the out pointer Plan takes for a registered type: a pointer to the interface
+// for interface types, a nil pointer of the type itself otherwise.
+func out(typeof reflect.Type) any {
+
+	if typeof.Kind() == reflect.Interface {
+		return reflect.New(typeof).Interface()
+	}
+
+	return reflect.Zero(typeof).Interface()
+}
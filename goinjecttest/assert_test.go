@@ -0,0 +1,127 @@
+package goinjecttest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	goinject "github.com/fobus1289/goInject"
+)
+
+type (
+	TestService struct {
+		Name string
+	}
+
+	TestRepository struct {
+		Service *TestService
+	}
+
+	TestHandler struct{}
+
+	AnotherService struct{}
+
+	TestReader interface {
+		Read() string
+	}
+
+	TestStore struct{}
+)
+
+func (*TestStore) Read() string { return "" }
+
+// recordingTB captures the failures reported through testing.TB.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertComplete(t *testing.T) {
+	c := goinject.New()
+	built := false
+
+	if err := c.Provide(func() *TestService {
+		built = true
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if err := goinject.RegisterAs[TestReader](c, &TestStore{}); err != nil {
+		t.Fatalf("failed to register reader: %v", err)
+	}
+
+	complete := &recordingTB{TB: t}
+	AssertComplete(complete, c)
+
+	if len(complete.errors) != 0 {
+		t.Errorf("AssertComplete() failed a complete graph with %v", complete.errors)
+	}
+
+	if err := c.Provide(func(*AnotherService) *TestHandler {
+		return &TestHandler{}
+	}); err != nil {
+		t.Fatalf("failed to provide handler: %v", err)
+	}
+
+	incomplete := &recordingTB{TB: t}
+	AssertComplete(incomplete, c)
+
+	if len(incomplete.errors) != 1 || !strings.Contains(incomplete.errors[0], "dependency *goinjecttest.AnotherService of *goinjecttest.TestHandler") {
+		t.Errorf("AssertComplete() reported %v, want the missing dependency", incomplete.errors)
+	}
+
+	if built {
+		t.Error("AssertComplete() should not run any factory")
+	}
+}
+
+func TestAssertComplete_Scope(t *testing.T) {
+	c := goinject.New()
+
+	if err := c.Provide(func() *TestService {
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	scope := c.Scope()
+
+	if err := scope.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	complete := &recordingTB{TB: t}
+	AssertComplete(complete, scope)
+
+	if len(complete.errors) != 0 {
+		t.Errorf("AssertComplete() failed a scope completed by its parent with %v", complete.errors)
+	}
+
+	if err := scope.Provide(func(*AnotherService) *TestHandler {
+		return &TestHandler{}
+	}); err != nil {
+		t.Fatalf("failed to provide handler: %v", err)
+	}
+
+	incomplete := &recordingTB{TB: t}
+	AssertComplete(incomplete, scope)
+
+	if len(incomplete.errors) != 1 {
+		t.Errorf("AssertComplete() reported %v, want the missing dependency", incomplete.errors)
+	}
+}
@@ -0,0 +1,281 @@
+package goinject
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// RegisterGroup adds an implementation to the named group.
+//
+// Example:
+//
+//	goinject.RegisterGroup[Middleware](container, "middleware", &Logging{})
+func RegisterGroup[T any](c *Container, group string, impl T) error {
+	return c.addToGroup(group, impl)
+}
+
+// RegisterGroupAll adds several implementations to the named group at once, preserving their order.
+//
+// Example:
+//
+//	goinject.RegisterGroupAll[Middleware](container, "middleware", &Recover{}, &Logging{}, &Auth{})
+func RegisterGroupAll[T any](c *Container, group string, impls ...T) error {
+
+	members := make([]any, len(impls))
+	for i, impl := range impls {
+		members[i] = impl
+	}
+
+	return c.addToGroup(group, members...)
+}
+
+// GetGroupNamed returns the members of the named group that are of type T, in registration order.
+//
+// Example:
+//
+//	for _, m := range goinject.GetGroupNamed[Middleware](container, "middleware") {
+//	    handler = m.Wrap(handler)
+//	}
+func GetGroupNamed[T any](c *Container, group string) []T {
+
+	c.mu.RLock()
+	members := slices.Clone(c.groups[group])
+	c.mu.RUnlock()
+
+	var impls []T
+	for _, member := range members {
+		if impl, ok := member.(T); ok {
+			impls = append(impls, impl)
+		}
+	}
+
+	return impls
+}
+
+// UnregisterGroup removes every member of the named group and returns how many were removed.
+// Registrations outside the group are untouched; a frozen container removes nothing.
+//
+// Example:
+//
+//	n := container.UnregisterGroup("plugins")
+func (c *Container) UnregisterGroup(group string) int {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.frozen {
+		return 0
+	}
+
+	n := len(c.groups[group])
+	delete(c.groups, group)
+
+	return n
+}
+
+// RegisterWithPriority registers a singleton instance with a priority used by GetSlice.
+// Registrations without a priority have the priority 0.
+//
+// Example:
+//
+//	container.RegisterWithPriority(&Recover{}, 100)
+//	container.RegisterWithPriority(&Logging{}, 10)
+func (c *Container) RegisterWithPriority(service any, priority int) error {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.addProvider(typeof, service); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.priorities[typeof] = priority
+
+	return nil
+}
+
+// GetSlice resolves every registration assignable to T, ordered by priority from highest
+// to lowest and by registration order among equal priorities. T is usually an interface;
+// its implementations are gathered even under WithStrictInterfaces.
+//
+// Example:
+//
+//	middleware, err := goinject.GetSlice[Middleware](container)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func GetSlice[T any](c *Container) ([]T, error) {
+
+	type member struct {
+		typeof   reflect.Type
+		priority int
+	}
+
+	c.mu.RLock()
+
+	types := c.matching(reflect.TypeFor[T]())
+
+	members := make([]member, len(types))
+	for i, typeof := range types {
+		members[i] = member{typeof, c.priorities[typeof]}
+	}
+
+	c.mu.RUnlock()
+
+	slices.SortStableFunc(members, func(a, b member) int {
+		return cmp.Compare(b.priority, a.priority)
+	})
+
+	impls := make([]T, 0, len(members))
+
+	for _, m := range members {
+		service, err := c.resolve(context.Background(), m.typeof)
+		{
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		impls = append(impls, service.(T))
+	}
+
+	return impls, nil
+}
+
+// GetInto fills the slice out points to with every registration assignable to its element type,
+// in registration order. It is the reflective counterpart of GetSlice: out is a *[]*T or a *[]I.
+//
+// Example:
+//
+//	var plugins []Plugin
+//	if err := container.GetInto(&plugins); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Container) GetInto(out any) error {
+
+	value := reflect.ValueOf(out)
+	{
+		if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+			return fmt.Errorf("%w to a slice, got %s", ErrOutputMustBeAPointer, c.nameOf(out))
+		}
+	}
+
+	slice := value.Elem()
+
+	services, err := c.collect(context.Background(), slice.Type().Elem())
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	members := reflect.MakeSlice(slice.Type(), 0, len(services))
+	for _, service := range services {
+		members = reflect.Append(members, reflect.ValueOf(service))
+	}
+
+	slice.Set(members)
+
+	return nil
+}
+
+// Group is a typed collection of implementations of T, identified by the handle itself
+// rather than by a name. Create it with NewGroup, fill it with Container.RegisterGroup
+// and resolve it with Resolve.
+type Group[T any] struct {
+	_ byte // Distinct groups must have distinct addresses.
+}
+
+// group is implemented by every Group and lets an untyped method accept typed groups.
+type group interface {
+	accepts(c *Container, impl any) error
+}
+
+// NewGroup creates a typed group of implementations of T.
+//
+// Example:
+//
+//	var Handlers = goinject.NewGroup[Handler]()
+func NewGroup[T any]() *Group[T] {
+	return &Group[T]{}
+}
+
+// accepts reports why the implementation cannot be a member of the group, if it cannot.
+func (g *Group[T]) accepts(c *Container, impl any) error {
+
+	if _, ok := impl.(T); !ok {
+		return fmt.Errorf("%s %w %s", c.nameOf(impl), ErrDoesNotImplement, c.name(reflect.TypeFor[T]()))
+	}
+
+	return nil
+}
+
+// Resolve returns the members of the group, in registration order.
+//
+// Example:
+//
+//	for _, h := range Handlers.Resolve(container) {
+//	    mux.Handle(h.Pattern(), h)
+//	}
+func (g *Group[T]) Resolve(c *Container) []T {
+
+	c.mu.RLock()
+	members := slices.Clone(c.typedGroups[g])
+	c.mu.RUnlock()
+
+	impls := make([]T, len(members))
+	for i, member := range members {
+		impls[i] = member.(T)
+	}
+
+	return impls
+}
+
+// RegisterGroup adds an implementation to the typed group created with NewGroup.
+// It returns an error if impl is not of the group's element type.
+//
+// Example:
+//
+//	container.RegisterGroup(Handlers, &UsersHandler{})
+func (c *Container) RegisterGroup(g group, impl any) error {
+
+	if err := g.accepts(c, impl); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.typedGroups[g] = append(c.typedGroups[g], impl)
+
+	return nil
+}
+
+// addToGroup appends members to the named group.
+func (c *Container) addToGroup(group string, members ...any) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.groups[group] = append(c.groups[group], members...)
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type (
+	TestMiddleware interface {
+		Wrap(string) string
+	}
+
+	TestMiddlewareFunc func(string) string
+)
+
+func (f TestMiddlewareFunc) Wrap(s string) string { return f(s) }
+
+func TestRegisterGroupAll(t *testing.T) {
+	c := New()
+
+	tag := func(name string) TestMiddleware {
+		return TestMiddlewareFunc(func(s string) string { return s + name })
+	}
+
+	if err := RegisterGroupAll(c, "middleware", tag("recover"), tag("logging"), tag("auth")); err != nil {
+		t.Fatalf("RegisterGroupAll() unexpected error = %v", err)
+	}
+
+	if err := RegisterGroup(c, "other", tag("unrelated")); err != nil {
+		t.Fatalf("RegisterGroup() unexpected error = %v", err)
+	}
+
+	middleware := GetGroupNamed[TestMiddleware](c, "middleware")
+
+	var got []string
+	for _, m := range middleware {
+		got = append(got, m.Wrap(""))
+	}
+
+	if want := []string{"recover", "logging", "auth"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGroupNamed[T]() got = %v, want %v", got, want)
+	}
+
+	if got := GetGroupNamed[TestMiddleware](c, "missing"); len(got) != 0 {
+		t.Errorf("GetGroupNamed[T]() for a missing group got = %v, want none", got)
+	}
+}
+
+// TestStage is a middleware whose type parameter only makes every instantiation a distinct registration.
+type TestStage[N any] struct {
+	Name string
+}
+
+func (s *TestStage[N]) Wrap(in string) string { return in + s.Name }
+
+func TestGetSlice(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterWithPriority(&TestStage[[1]int]{Name: "low"}, 1); err != nil {
+		t.Fatalf("RegisterWithPriority() unexpected error = %v", err)
+	}
+
+	if err := c.Register(&TestStage[[2]int]{Name: "default"}); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterWithPriority(&TestStage[[3]int]{Name: "high"}, 10); err != nil {
+		t.Fatalf("RegisterWithPriority() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterWithPriority(&TestStage[[4]int]{Name: "tied"}, 1); err != nil {
+		t.Fatalf("RegisterWithPriority() unexpected error = %v", err)
+	}
+
+	if err := c.Register(&TestService{Name: "unrelated"}); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	middleware, err := GetSlice[TestMiddleware](c)
+	if err != nil {
+		t.Fatalf("GetSlice[T]() unexpected error = %v", err)
+	}
+
+	var got []string
+	for _, m := range middleware {
+		got = append(got, m.Wrap(""))
+	}
+
+	if want := []string{"high", "low", "tied", "default"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSlice[T]() got = %v, want %v", got, want)
+	}
+}
+
+func TestContainer_UnregisterGroup(t *testing.T) {
+	c := New()
+
+	tag := func(name string) TestMiddleware {
+		return TestMiddlewareFunc(func(s string) string { return s + name })
+	}
+
+	if err := RegisterGroupAll(c, "plugins", tag("a"), tag("b")); err != nil {
+		t.Fatalf("RegisterGroupAll() unexpected error = %v", err)
+	}
+
+	if err := RegisterGroup(c, "other", tag("c")); err != nil {
+		t.Fatalf("RegisterGroup() unexpected error = %v", err)
+	}
+
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	if got := c.UnregisterGroup("plugins"); got != 2 {
+		t.Errorf("UnregisterGroup() got = %v, want %v", got, 2)
+	}
+
+	if got := GetGroupNamed[TestMiddleware](c, "plugins"); len(got) != 0 {
+		t.Errorf("GetGroupNamed[T]() after UnregisterGroup got = %v, want none", got)
+	}
+
+	if got := GetGroupNamed[TestMiddleware](c, "other"); len(got) != 1 {
+		t.Errorf("GetGroupNamed[T]() for another group got = %v, want one member", got)
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Errorf("Get[T]() unexpected error = %v", err)
+	}
+
+	if got := c.UnregisterGroup("plugins"); got != 0 {
+		t.Errorf("UnregisterGroup() for a removed group got = %v, want %v", got, 0)
+	}
+}
+
+func TestContainer_GetInto(t *testing.T) {
+	c := New()
+	a, b := &TestPluginA{}, &TestPluginB{}
+	service := &TestService{Name: "test"}
+
+	for _, s := range []any{b, service, a} {
+		if err := c.Register(s); err != nil {
+			t.Fatalf("Register() unexpected error = %v", err)
+		}
+	}
+
+	var plugins []TestPlugin
+	if err := c.GetInto(&plugins); err != nil {
+		t.Fatalf("GetInto() unexpected error = %v", err)
+	}
+
+	if want := []TestPlugin{b, a}; !reflect.DeepEqual(plugins, want) {
+		t.Errorf("GetInto() got = %v, want %v", plugins, want)
+	}
+
+	var services []*TestService
+	if err := c.GetInto(&services); err != nil {
+		t.Fatalf("GetInto() unexpected error = %v", err)
+	}
+
+	if len(services) != 1 || services[0] != service {
+		t.Errorf("GetInto() got = %v, want [%v]", services, service)
+	}
+
+	if err := c.GetInto(&TestService{}); !errors.Is(err, ErrOutputMustBeAPointer) {
+		t.Errorf("GetInto() error = %v, want %v", err, ErrOutputMustBeAPointer)
+	}
+}
+
+func TestGroup_Resolve(t *testing.T) {
+	c := New()
+	middleware := NewGroup[TestMiddleware]()
+	other := NewGroup[TestMiddleware]()
+
+	for _, name := range []string{"recover", "logging", "auth"} {
+		if err := c.RegisterGroup(middleware, TestMiddlewareFunc(func(s string) string { return s + name })); err != nil {
+			t.Fatalf("RegisterGroup() unexpected error = %v", err)
+		}
+	}
+
+	if err := c.RegisterGroup(middleware, &TestService{}); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("RegisterGroup() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+
+	var got []string
+	for _, m := range middleware.Resolve(c) {
+		got = append(got, m.Wrap(""))
+	}
+
+	if want := []string{"recover", "logging", "auth"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() got = %v, want %v", got, want)
+	}
+
+	if got := other.Resolve(c); len(got) != 0 {
+		t.Errorf("Resolve() of another group got = %v, want none", got)
+	}
+}
@@ -0,0 +1,86 @@
+package goinject
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// keyedFactory builds and memoizes one instance per key.
+type keyedFactory[K comparable, T any] struct {
+	call      func(K) *T
+	mu        sync.Mutex
+	instances map[K]*keyedInstance[T]
+}
+
+// fresh returns a copy of the keyed factory that has not built anything yet.
+func (f *keyedFactory[K, T]) fresh() any {
+	return &keyedFactory[K, T]{call: f.call, instances: make(map[K]*keyedInstance[T])}
+}
+
+// keyedInstance is the instance built for one key, built at most once.
+type keyedInstance[T any] struct {
+	once     sync.Once
+	instance *T
+}
+
+// RegisterKeyedFactory registers a factory that builds one instance of T per key.
+// The instance for a key is built on its first GetKeyedFactory and reused afterwards.
+//
+// Example:
+//
+//	goinject.RegisterKeyedFactory(container, func(tenant TenantID) *DB {
+//	    return OpenTenantDB(tenant)
+//	})
+//	db, err := goinject.GetKeyedFactory[TenantID, DB](container, "acme")
+func RegisterKeyedFactory[K comparable, T any](c *Container, f func(K) *T) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.keyed[reflect.TypeFor[func(K) *T]()] = &keyedFactory[K, T]{call: f, instances: make(map[K]*keyedInstance[T])}
+
+	return nil
+}
+
+// GetKeyedFactory returns the instance of T for the key, building it on first use.
+// It returns an error matching ErrServiceNotFound if no keyed factory is registered for K and T.
+//
+// Example:
+//
+//	db, err := goinject.GetKeyedFactory[TenantID, DB](container, tenant)
+func GetKeyedFactory[K comparable, T any](c *Container, key K) (*T, error) {
+
+	typeof := reflect.TypeFor[func(K) *T]()
+
+	c.mu.RLock()
+	f, _ := c.keyed[typeof].(*keyedFactory[K, T])
+	c.mu.RUnlock()
+
+	if c.closed.Load() {
+		return nil, ErrContainerClosed
+	}
+
+	if f == nil {
+		return nil, fmt.Errorf("%w: keyed factory %s", ErrServiceNotFound, c.name(typeof))
+	}
+
+	f.mu.Lock()
+	entry := f.instances[key]
+	if entry == nil {
+		entry = &keyedInstance[T]{}
+		f.instances[key] = entry
+	}
+	f.mu.Unlock()
+
+	// Building outside the map lock lets other keys proceed meanwhile.
+	entry.once.Do(func() {
+		entry.instance = f.call(key)
+	})
+
+	return entry.instance, nil
+}
@@ -0,0 +1,60 @@
+package goinject
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetKeyedFactory(t *testing.T) {
+	c := New()
+	var builds atomic.Int32
+
+	if err := RegisterKeyedFactory(c, func(id int) *AnotherService {
+		builds.Add(1)
+		return &AnotherService{ID: id}
+	}); err != nil {
+		t.Fatalf("RegisterKeyedFactory() unexpected error = %v", err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		instances [3]sync.Map
+	)
+
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			key := i % 3
+
+			service, err := GetKeyedFactory[int, AnotherService](c, key)
+			if err != nil || service.ID != key {
+				t.Errorf("GetKeyedFactory() got = %v, %v, want ID %v", service, err, key)
+				return
+			}
+
+			instances[key].Store(service, true)
+		}()
+	}
+	wg.Wait()
+
+	for key := range instances {
+		n := 0
+		instances[key].Range(func(any, any) bool { n++; return true })
+
+		if n != 1 {
+			t.Errorf("GetKeyedFactory() returned %d instances for key %d, want 1", n, key)
+		}
+	}
+
+	if got := builds.Load(); got != 3 {
+		t.Errorf("factory ran %d times, want 3", got)
+	}
+
+	if _, err := GetKeyedFactory[string, AnotherService](c, "a"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("GetKeyedFactory() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
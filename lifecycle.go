@@ -0,0 +1,633 @@
+package goinject
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// ContainerState describes where a container is in its lifecycle.
+type ContainerState int
+
+const (
+	// Open containers accept registrations and resolutions.
+	Open ContainerState = iota
+	// Frozen containers resolve but reject registrations, see Freeze.
+	Frozen
+	// Closed containers reject registrations and resolutions, see Close.
+	Closed
+)
+
+// String returns the name of the state.
+func (s ContainerState) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case Frozen:
+		return "frozen"
+	case Closed:
+		return "closed"
+	default:
+		return "ContainerState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// State returns the lifecycle state of the container without affecting it.
+//
+// Example:
+//
+//	if container.State() == goinject.Open {
+//	    container.Register(&Config{})
+//	}
+func (c *Container) State() ContainerState {
+
+	if c.closed.Load() {
+		return Closed
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.frozen {
+		return Frozen
+	}
+
+	return Open
+}
+
+// Disposable is implemented by services that release resources when the container is closed.
+type Disposable interface {
+	Dispose() error
+}
+
+// Startable is implemented by services that StartAll starts.
+type Startable interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by started services that StartAll stops again when a later service fails to start.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// StartAll resolves every registration implementing Startable and starts them in dependency order,
+// dependencies first. If a service fails to start, the services already started are stopped
+// in reverse order if they implement Stopper, and every error is joined.
+//
+// Example:
+//
+//	if err := container.StartAll(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Container) StartAll(ctx context.Context) error {
+
+	c.mu.RLock()
+
+	types := c.matching(reflect.TypeFor[Startable]())
+
+	// Interface-keyed factories are matched by what they build.
+	for _, typeof := range c.order {
+		if factory := c.factories[typeof]; factory != nil && typeof.Kind() == reflect.Interface &&
+			factory.concrete.Implements(reflect.TypeFor[Startable]()) {
+			types = append(types, typeof)
+		}
+	}
+
+	c.mu.RUnlock()
+
+	var startables []Startable
+
+	for _, typeof := range types {
+		service, err := c.resolve(ctx, typeof)
+		{
+			if err != nil {
+				return fmt.Errorf("%s: %w", c.name(typeof), err)
+			}
+		}
+
+		if startable := service.(Startable); !slices.Contains(startables, startable) {
+			startables = append(startables, startable)
+		}
+	}
+
+	// Dependencies come into existence before their dependents.
+	c.mu.RLock()
+	created := make(map[Startable]int, len(startables))
+	for i, typeof := range c.materialized {
+		if startable, ok := c.instance(typeof).(Startable); ok {
+			if _, seen := created[startable]; !seen {
+				created[startable] = i
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	slices.SortStableFunc(startables, func(a, b Startable) int {
+		return cmp.Compare(created[a], created[b])
+	})
+
+	for i, startable := range startables {
+		if err := startable.Start(ctx); err != nil {
+			errs := []error{fmt.Errorf("start %s: %w", c.nameOf(startable), err)}
+
+			for j := i - 1; j >= 0; j-- {
+				if stopper, ok := startables[j].(Stopper); ok {
+					if err := stopper.Stop(ctx); err != nil {
+						errs = append(errs, fmt.Errorf("stop %s: %w", c.nameOf(stopper), err))
+					}
+				}
+			}
+
+			return errors.Join(errs...)
+		}
+	}
+
+	return nil
+}
+
+// RegisterWithFinalizer registers a singleton instance together with a teardown function.
+// On Close the container calls finalize with the instance instead of Dispose,
+// which allows cleaning up types that cannot implement Disposable.
+//
+// Example:
+//
+//	container.RegisterWithFinalizer(db, func(service any) error {
+//	    return service.(*sql.DB).Close()
+//	})
+func (c *Container) RegisterWithFinalizer(service any, finalize func(any) error) error {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	service, err = c.registering(typeof, service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	// The instance and its finalizer go in together, so that Close never sees one without the other.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.putProvider(typeof, service)
+	c.finalizers[typeof] = finalize
+
+	return nil
+}
+
+// RegisterOrdered registers a singleton instance, or a factory function like RegisterFactory,
+// with an order hint. Build constructs singletons in ascending order of their hints and Close
+// disposes them in descending order. Registrations without a hint have the hint 0, and
+// registrations with the same hint keep their registration order.
+//
+// Example:
+//
+//	container.RegisterOrdered(NewDB, 1)
+//	container.RegisterOrdered(NewCache, 2)
+func (c *Container) RegisterOrdered(service any, order int) error {
+
+	var typeof typeof
+
+	if reflect.TypeOf(service) != nil && reflect.TypeOf(service).Kind() == reflect.Func {
+		t, factory, err := c.parseFactory(service)
+		{
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := c.addFactory(t, factory); err != nil {
+			return err
+		}
+
+		typeof = t
+	} else {
+		t, service, err := c.provider(service)
+		{
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := c.addProvider(t, service); err != nil {
+			return err
+		}
+
+		typeof = t
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hints[typeof] = order
+
+	return nil
+}
+
+// Build constructs every registered singleton that is not built yet, in ascending order
+// of the hints given to RegisterOrdered and in registration order otherwise.
+// It stops at the first factory that fails and returns its error. Once every singleton is
+// built, the hooks registered with OnBuilt run. Scoped factories are left to the scopes that
+// resolve them. Before building anything, it checks that the dependencies declared by
+// registered instances implementing DependencyDeclarer can be resolved and that the target
+// of every interface binding implements the interface, which it checks again on the
+// instances once everything is built; a mismatch fails with ErrDoesNotImplement.
+//
+// Example:
+//
+//	if err := container.Build(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Container) Build() error {
+
+	type pending struct {
+		typeof  typeof
+		factory *factory
+		order   int
+	}
+
+	if err := c.declared(); err != nil {
+		return err
+	}
+
+	if err := c.bound(); err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+
+	builds := make([]pending, 0, len(c.factories))
+
+	for _, typeof := range c.order {
+		if factory := c.factories[typeof]; factory != nil && factory.eager() {
+			builds = append(builds, pending{typeof, factory, c.hints[typeof]})
+		}
+	}
+
+	c.mu.RUnlock()
+
+	slices.SortStableFunc(builds, func(a, b pending) int {
+		return cmp.Compare(a.order, b.order)
+	})
+
+	for _, b := range builds {
+		if c.closed.Load() {
+			return ErrContainerClosed
+		}
+
+		if _, err := c.build(context.Background(), b.typeof, b.factory); err != nil {
+			return fmt.Errorf("%s: %w", c.name(b.typeof), err)
+		}
+	}
+
+	// The instances built above may not match the types their factories declared.
+	if err := c.bound(); err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	hooks := slices.Clone(c.built)
+	c.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// declared checks, without invoking any factory, that the dependencies declared
+// by the registered instances can be resolved.
+func (c *Container) declared() error {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, typeof := range c.order {
+		declarer, ok := c.providers[typeof].(DependencyDeclarer)
+		if !ok {
+			continue
+		}
+
+		for _, dep := range declarer.Dependencies() {
+			if _, err := c.plan(dep); err != nil {
+				return fmt.Errorf("dependency %s of %s: %w", c.name(dep), c.name(typeof), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// bound checks that the target of every binding implements its interface,
+// and so does the instance of the target if it exists, in the order of the interface names.
+func (c *Container) bound() error {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ifaces := slices.SortedFunc(maps.Keys(c.bindings), func(a, b typeof) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	for _, iface := range ifaces {
+		target := c.bindings[iface]
+
+		if !target.Implements(iface) {
+			return fmt.Errorf("binding of %s to %s: %w", c.name(iface), c.name(target), ErrDoesNotImplement)
+		}
+
+		if instance := c.instance(target); instance != nil && !reflect.TypeOf(instance).Implements(iface) {
+			return fmt.Errorf("binding of %s to %s: instance %s %w", c.name(iface), c.name(target), c.nameOf(instance), ErrDoesNotImplement)
+		}
+	}
+
+	return nil
+}
+
+// OnBuilt registers a hook that Build runs once every singleton is built.
+// Hooks run in registration order, and the first that fails stops Build with its error.
+//
+// Example:
+//
+//	container.OnBuilt(func(c *goinject.Container) error {
+//	    worker := goinject.MustGet[Worker](c)
+//	    go worker.Run()
+//	    return nil
+//	})
+func (c *Container) OnBuilt(fn func(c *Container) error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.built = append(c.built, fn)
+}
+
+// Warm constructs the listed singletons and their dependencies now, leaving every other
+// factory lazy. Types are given like the out pointer of Get, a nil pointer is enough.
+// Types registered with a lifetime that keeps no shared instance, transient, scoped or
+// goroutine-local, are skipped, as nothing built for them would be kept.
+// It stops at the first type that cannot be resolved and returns its error.
+//
+// Example:
+//
+//	err := container.Warm((*UserRepository)(nil), (*Cache)(nil))
+func (c *Container) Warm(types ...any) error {
+
+	for _, out := range types {
+		typeof, err := keyOf(out)
+		{
+			if err != nil {
+				return err
+			}
+		}
+
+		c.mu.RLock()
+		factory := c.factories[typeof]
+		c.mu.RUnlock()
+
+		if factory != nil && !factory.eager() {
+			continue
+		}
+
+		if _, err := c.resolve(context.Background(), typeof); err != nil {
+			return fmt.Errorf("%s: %w", c.name(typeof), err)
+		}
+	}
+
+	return nil
+}
+
+// MaterializedTypes returns the types that have an instance, registered or built,
+// in the order the instances came to exist.
+//
+// Example:
+//
+//	container.Warm((*UserRepository)(nil))
+//	fmt.Println(container.MaterializedTypes()) // [*main.DB *main.UserRepository]
+func (c *Container) MaterializedTypes() []reflect.Type {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.materialized)
+}
+
+// VerifyDisposable reports the factories building a Disposable that Build never materializes
+// and Close therefore never disposes: the transient and goroutine-local ones in registration
+// order, then the named factories, the constructors given to ProvideNamed and the keyed
+// factories, sorted by type name. Their instances leak unless whoever resolves them disposes them.
+//
+// Example:
+//
+//	if leaks := container.VerifyDisposable(); len(leaks) > 0 {
+//	    log.Printf("never disposed by Close: %v", leaks)
+//	}
+func (c *Container) VerifyDisposable() []reflect.Type {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var types []reflect.Type
+
+	for _, typeof := range c.order {
+		factory := c.factories[typeof]
+		if factory == nil || factory.lifetime != Transient && factory.lifetime != GoroutineLocal {
+			continue
+		}
+
+		if factory.concrete.Implements(reflect.TypeFor[Disposable]()) {
+			types = append(types, typeof)
+		}
+	}
+
+	var named []reflect.Type
+
+	for typeof := range c.namedFactories {
+		named = append(named, typeof)
+	}
+
+	for key := range c.namedCtors {
+		named = append(named, key.typeof)
+	}
+
+	// A keyed factory is registered under its func(K) *T signature.
+	for typeof := range c.keyed {
+		named = append(named, typeof.Out(0))
+	}
+
+	named = slices.DeleteFunc(named, func(t reflect.Type) bool {
+		return !t.Implements(reflect.TypeFor[Disposable]())
+	})
+
+	slices.SortFunc(named, func(a, b reflect.Type) int {
+		return cmp.Compare(c.name(a), c.name(b))
+	})
+
+	for _, typeof := range slices.Compact(named) {
+		if !slices.Contains(types, typeof) {
+			types = append(types, typeof)
+		}
+	}
+
+	return types
+}
+
+// Freeze prevents any further registration, which then returns ErrContainerFrozen.
+// With WithCopyOnWrite the registrations are published to a lock-free read path.
+//
+// Example:
+//
+//	container.Register(&User{ID: 1, Name: "John"})
+//	container.Freeze()
+func (c *Container) Freeze() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.frozen {
+		return
+	}
+
+	c.frozen = true
+
+	if c.copyOnWrite {
+		c.snapshot.Store(&registry{
+			providers: maps.Clone(c.providers),
+			factories: maps.Clone(c.factories),
+			bindings:  maps.Clone(c.bindings),
+		})
+	}
+}
+
+// Close disposes every materialized instance in reverse order of creation, or in
+// descending order of the hints given to RegisterOrdered, and
+// rejects any further registration or resolution with ErrContainerClosed.
+// Instances registered with a finalizer are passed to it, other instances are
+// disposed if they implement Disposable. All teardown errors are joined.
+// While Close disposes, instances that already exist can still be resolved, so that
+// Dispose may reach its collaborators, in the parents too for a scope; resolving anything
+// else returns ErrContainerClosed.
+// The channel returned by Events is closed once everything is disposed.
+//
+// Example:
+//
+//	defer container.Close()
+func (c *Container) Close() error {
+
+	c.mu.Lock()
+
+	if c.closed.Load() {
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.closed.Store(true)
+
+	type disposal struct {
+		typeof   typeof
+		instance any
+		finalize func(any) error
+		order    int
+	}
+
+	disposals := make([]disposal, 0, len(c.materialized))
+
+	for _, typeof := range c.materialized {
+		instance := c.instance(typeof)
+		if instance == nil {
+			continue
+		}
+
+		disposals = append(disposals, disposal{typeof, instance, c.finalizers[typeof], c.hints[typeof]})
+	}
+
+	// Order hints take precedence, instances with the same hint keep their creation order.
+	slices.SortStableFunc(disposals, func(a, b disposal) int {
+		return cmp.Compare(a.order, b.order)
+	})
+
+	c.materialized = nil
+
+	// Teardown runs without the lock so that it may call back into the container.
+	c.mu.Unlock()
+
+	var (
+		errs       []error
+		disposedOf []typeof
+	)
+
+	// Once everything is disposed, nothing is resolvable anymore.
+	defer c.disposed.Store(true)
+	defer c.events.close()
+
+	// An instance materialized under several types is torn down once.
+	done := make(map[any]bool)
+
+	for i := len(disposals) - 1; i >= 0; i-- {
+		d := disposals[i]
+
+		if reflect.ValueOf(d.instance).Kind() == reflect.Pointer {
+			if done[d.instance] {
+				continue
+			}
+
+			done[d.instance] = true
+		}
+
+		var err error
+
+		if d.finalize != nil {
+			err = d.finalize(d.instance)
+		} else if disposable, ok := d.instance.(Disposable); ok {
+			err = disposable.Dispose()
+			disposedOf = append(disposedOf, d.typeof)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.name(d.typeof), err))
+		}
+	}
+
+	c.mu.Lock()
+	c.disposedOf = disposedOf
+	c.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// DisposedTypes returns the types whose instance Close disposed by calling Dispose,
+// in the order they were disposed; instances passed to a finalizer are not included.
+// Before Close it returns nil. Close disposes every instance at most once, even when
+// it is called again or the instance was materialized under several types, so tests
+// can assert that cleanup happened exactly as expected.
+//
+// Example:
+//
+//	container.Close()
+//	if !slices.Contains(container.DisposedTypes(), reflect.TypeFor[*DB]()) {
+//	    t.Error("DB was not disposed")
+//	}
+func (c *Container) DisposedTypes() []reflect.Type {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.disposedOf)
+}
@@ -0,0 +1,594 @@
+package goinject
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type (
+	TestDisposable struct {
+		Name     string
+		disposed *[]string
+	}
+)
+
+// TestStartable records its lifecycle calls and fails to start when err is set.
+type TestStartable struct {
+	Name string
+	log  *[]string
+	err  error
+}
+
+func (s *TestStartable) Start(context.Context) error {
+	*s.log = append(*s.log, "start "+s.Name)
+	return s.err
+}
+
+func (s *TestStartable) Stop(context.Context) error {
+	*s.log = append(*s.log, "stop "+s.Name)
+	return nil
+}
+
+func (d *TestDisposable) Dispose() error {
+	*d.disposed = append(*d.disposed, d.Name)
+	return nil
+}
+
+func TestContainer_RegisterWithFinalizer(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+
+	var finalized any
+	if err := c.RegisterWithFinalizer(service, func(instance any) error {
+		finalized = instance
+		return nil
+	}); err != nil {
+		t.Fatalf("RegisterWithFinalizer() unexpected error = %v", err)
+	}
+
+	if finalized != nil {
+		t.Fatal("finalizer should not run before Close")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if finalized != service {
+		t.Errorf("finalizer got = %v, want %v", finalized, service)
+	}
+}
+
+func TestContainer_Close(t *testing.T) {
+	var disposed []string
+	c := New()
+
+	if err := c.Register(&TestDisposable{Name: "first", disposed: &disposed}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "never built"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	type Second struct{ TestDisposable }
+	if err := c.RegisterFactory(func() *Second {
+		return &Second{TestDisposable{Name: "second", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if _, err := Get[Second](c); err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if want := []string{"second", "first"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("Close() disposed = %v, want %v", disposed, want)
+	}
+
+	if _, err := Get[Second](c); !errors.Is(err, ErrContainerClosed) {
+		t.Errorf("Get[T]() after Close error = %v, want %v", err, ErrContainerClosed)
+	}
+
+	if err := c.Register(&TestService{}); !errors.Is(err, ErrContainerClosed) {
+		t.Errorf("Register() after Close error = %v, want %v", err, ErrContainerClosed)
+	}
+}
+
+func TestContainer_Freeze(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	c.Freeze()
+
+	if err := c.Register(&AnotherService{}); !errors.Is(err, ErrContainerFrozen) {
+		t.Errorf("Register() after Freeze error = %v, want %v", err, ErrContainerFrozen)
+	}
+
+	if c.Unregister((*TestService)(nil)) {
+		t.Error("Unregister() after Freeze got = true, want false")
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Errorf("Get[T]() after Freeze unexpected error = %v", err)
+	}
+}
+
+func TestContainer_RegisterOrdered(t *testing.T) {
+	c := New()
+	var built, disposed []string
+
+	type First struct{ TestDisposable }
+	type Second struct{ TestDisposable }
+	type Third struct{ TestDisposable }
+
+	// Registered in reverse, the hints decide the order.
+	if err := c.RegisterOrdered(func() *Third {
+		built = append(built, "third")
+		return &Third{TestDisposable{Name: "third", disposed: &disposed}}
+	}, 3); err != nil {
+		t.Fatalf("RegisterOrdered() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterOrdered(&Second{TestDisposable{Name: "second", disposed: &disposed}}, 2); err != nil {
+		t.Fatalf("RegisterOrdered() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterOrdered(func() *First {
+		built = append(built, "first")
+		return &First{TestDisposable{Name: "first", disposed: &disposed}}
+	}, 1); err != nil {
+		t.Fatalf("RegisterOrdered() unexpected error = %v", err)
+	}
+
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build() unexpected error = %v", err)
+	}
+
+	if want := []string{"first", "third"}; !reflect.DeepEqual(built, want) {
+		t.Errorf("Build() order = %v, want %v", built, want)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if want := []string{"third", "second", "first"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("Close() order = %v, want %v", disposed, want)
+	}
+}
+
+func TestContainer_Build(t *testing.T) {
+	c := New()
+	errBroken := errors.New("broken")
+
+	if err := c.Provide(func() (*TestService, error) {
+		return nil, errBroken
+	}); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Build(); !errors.Is(err, errBroken) {
+		t.Errorf("Build() error = %v, want %v", err, errBroken)
+	}
+
+	if err := c.RegisterOrdered(42, 1); !errors.Is(err, ErrOutputMustBeAPointer) {
+		t.Errorf("RegisterOrdered() error = %v, want %v", err, ErrOutputMustBeAPointer)
+	}
+}
+
+func TestContainer_Warm(t *testing.T) {
+	c := New()
+
+	if err := c.Provide(func() *TestService {
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if err := c.Provide(func() *AnotherService {
+		return &AnotherService{ID: 1}
+	}); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if got := c.MaterializedTypes(); len(got) != 0 {
+		t.Errorf("MaterializedTypes() before Warm got = %v, want none", got)
+	}
+
+	if err := c.Warm((*TestRepository)(nil)); err != nil {
+		t.Fatalf("Warm() unexpected error = %v", err)
+	}
+
+	want := []reflect.Type{reflect.TypeOf(&TestService{}), reflect.TypeOf(&TestRepository{})}
+	if got := c.MaterializedTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MaterializedTypes() got = %v, want %v", got, want)
+	}
+
+	if err := c.Warm((*TestHandler)(nil)); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Warm() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	built := 0
+	if err := c.RegisterTransient(func() *TestHandler {
+		built++
+		return &TestHandler{}
+	}); err != nil {
+		t.Fatalf("failed to register transient: %v", err)
+	}
+
+	if err := c.Warm((*TestHandler)(nil)); err != nil || built != 0 {
+		t.Errorf("Warm() of a transient got = %v and %d builds, want it skipped", err, built)
+	}
+}
+
+func TestContainer_State(t *testing.T) {
+	c := New()
+
+	if got := c.State(); got != Open {
+		t.Errorf("State() got = %v, want %v", got, Open)
+	}
+
+	c.Freeze()
+
+	if got := c.State(); got != Frozen {
+		t.Errorf("State() after Freeze got = %v, want %v", got, Frozen)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if got := c.State(); got != Closed {
+		t.Errorf("State() after Close got = %v, want %v", got, Closed)
+	}
+
+	if got := ContainerState(7).String(); got != "ContainerState(7)" {
+		t.Errorf("String() got = %v, want %v", got, "ContainerState(7)")
+	}
+}
+
+func TestContainer_StartAll(t *testing.T) {
+	type Database struct{ TestStartable }
+	type Server struct {
+		TestStartable
+		Database *Database
+	}
+
+	errPort := errors.New("port in use")
+
+	tests := []struct {
+		name    string
+		err     error
+		wantLog []string
+	}{
+		{"all start", nil, []string{"start database", "start server"}},
+		{"rollback", errPort, []string{"start database", "start server", "stop database"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			var log []string
+
+			// The dependent is registered first, the dependency still starts first.
+			if err := c.Provide(func(db *Database) *Server {
+				return &Server{TestStartable{Name: "server", log: &log, err: tt.err}, db}
+			}); err != nil {
+				t.Fatalf("failed to provide server: %v", err)
+			}
+
+			if err := c.Provide(func() *Database {
+				return &Database{TestStartable{Name: "database", log: &log}}
+			}); err != nil {
+				t.Fatalf("failed to provide database: %v", err)
+			}
+
+			if err := c.Register(&TestService{Name: "not startable"}); err != nil {
+				t.Fatalf("failed to register service: %v", err)
+			}
+
+			if err := c.StartAll(context.Background()); !errors.Is(err, tt.err) {
+				t.Errorf("StartAll() error = %v, want %v", err, tt.err)
+			}
+
+			if !reflect.DeepEqual(log, tt.wantLog) {
+				t.Errorf("StartAll() calls = %v, want %v", log, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestContainer_OnBuilt(t *testing.T) {
+	c := New()
+	var calls []string
+
+	if err := c.RegisterFactory(func() *TestService {
+		calls = append(calls, "factory")
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	c.OnBuilt(func(c *Container) error {
+		if _, err := c.GetOpts(&TestService{}, OnlyCached()); err != nil {
+			t.Errorf("OnBuilt hook ran before the singletons were built: %v", err)
+		}
+
+		calls = append(calls, "first")
+		return nil
+	})
+
+	errHook := errors.New("hook failed")
+	c.OnBuilt(func(*Container) error {
+		calls = append(calls, "second")
+		return errHook
+	})
+
+	c.OnBuilt(func(*Container) error {
+		calls = append(calls, "third")
+		return nil
+	})
+
+	if err := c.Build(); !errors.Is(err, errHook) {
+		t.Errorf("Build() error = %v, want %v", err, errHook)
+	}
+
+	if want := []string{"factory", "first", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Build() calls = %v, want %v", calls, want)
+	}
+}
+
+// TestFlusher resolves a collaborator from the container while it is disposed.
+type TestFlusher struct {
+	c        *Container
+	flushed  *TestService
+	notBuilt error
+}
+
+func (f *TestFlusher) Dispose() error {
+
+	service, err := Get[TestService](f.c)
+	if err != nil {
+		return err
+	}
+
+	f.flushed = service
+	_, f.notBuilt = Get[AnotherService](f.c)
+
+	return nil
+}
+
+func TestContainer_Close_ResolveDuringDispose(t *testing.T) {
+	c := New()
+	flusher := &TestFlusher{c: c}
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "collaborator"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.RegisterFactory(func() *AnotherService {
+		return &AnotherService{ID: 1}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if err := c.Register(flusher); err != nil {
+		t.Fatalf("failed to register flusher: %v", err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if flusher.flushed == nil || flusher.flushed.Name != "collaborator" {
+		t.Errorf("Dispose() resolved %v, want the materialized collaborator", flusher.flushed)
+	}
+
+	if !errors.Is(flusher.notBuilt, ErrContainerClosed) {
+		t.Errorf("Dispose() resolving an unbuilt service error = %v, want %v", flusher.notBuilt, ErrContainerClosed)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrContainerClosed) {
+		t.Errorf("Get[T]() after Close error = %v, want %v", err, ErrContainerClosed)
+	}
+}
+
+func TestContainer_VerifyDisposable(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterFactory(func() *TestCachedA { return &TestCachedA{} }); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.RegisterTransient(func() *TestCachedB { return &TestCachedB{} }); err != nil {
+		t.Fatalf("failed to register transient: %v", err)
+	}
+
+	if err := c.RegisterGoroutineLocal(func() *TestCachedC { return &TestCachedC{} }); err != nil {
+		t.Fatalf("failed to register goroutine-local: %v", err)
+	}
+
+	if err := c.RegisterTransient(func() *TestService { return &TestService{} }); err != nil {
+		t.Fatalf("failed to register transient: %v", err)
+	}
+
+	want := []reflect.Type{reflect.TypeFor[*TestCachedB](), reflect.TypeFor[*TestCachedC]()}
+
+	if got := c.VerifyDisposable(); !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyDisposable() got = %v, want %v", got, want)
+	}
+
+	type (
+		Named struct{ TestDisposable }
+		Keyed struct{ TestDisposable }
+	)
+
+	if err := c.ProvideNamed("primary", func() *TestDisposable { return &TestDisposable{} }); err != nil {
+		t.Fatalf("failed to provide named constructor: %v", err)
+	}
+
+	if err := c.ProvideNamed("replica", func() *TestDisposable { return &TestDisposable{} }); err != nil {
+		t.Fatalf("failed to provide named constructor: %v", err)
+	}
+
+	if err := c.RegisterNamedFactory(func(string) *Named { return &Named{} }); err != nil {
+		t.Fatalf("failed to register named factory: %v", err)
+	}
+
+	if err := RegisterKeyedFactory(c, func(int) *Keyed { return &Keyed{} }); err != nil {
+		t.Fatalf("failed to register keyed factory: %v", err)
+	}
+
+	if err := RegisterKeyedFactory(c, func(int) *TestService { return &TestService{} }); err != nil {
+		t.Fatalf("failed to register keyed factory: %v", err)
+	}
+
+	want = append(want, reflect.TypeFor[*Keyed](), reflect.TypeFor[*Named](), reflect.TypeFor[*TestDisposable]())
+
+	if got := c.VerifyDisposable(); !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyDisposable() got = %v, want %v", got, want)
+	}
+}
+
+func TestContainer_DisposedTypes(t *testing.T) {
+	c := New()
+
+	type (
+		Second struct{ TestDisposable }
+		Third  struct{ TestDisposable }
+	)
+
+	var disposed []string
+
+	if err := c.Register(&TestDisposable{Name: "first", disposed: &disposed}); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterWithFinalizer(&TestService{Name: "finalized"}, func(any) error { return nil }); err != nil {
+		t.Fatalf("RegisterWithFinalizer() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func() *Second {
+		return &Second{TestDisposable{Name: "second", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func() *Third {
+		return &Third{TestDisposable{Name: "third", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	MustGet[Second](c)
+
+	if got := c.DisposedTypes(); got != nil {
+		t.Errorf("DisposedTypes() before Close got = %v, want nil", got)
+	}
+
+	for range 2 {
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close() unexpected error = %v", err)
+		}
+	}
+
+	want := []reflect.Type{reflect.TypeFor[*Second](), reflect.TypeFor[*TestDisposable]()}
+	if got := c.DisposedTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DisposedTypes() got = %v, want %v", got, want)
+	}
+
+	if want := []string{"second", "first"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("Close() twice disposed %v, want %v once each", disposed, want)
+	}
+}
+
+func TestContainer_BuildValidatesBindings(t *testing.T) {
+	c := New()
+
+	if err := RegisterAs[TestReader](c, &TestStore{}); err != nil {
+		t.Fatalf("RegisterAs[TestReader]() unexpected error = %v", err)
+	}
+
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build() unexpected error = %v", err)
+	}
+
+	// A binding left behind by a refactor, its target no longer implements the interface.
+	c.bindings[reflect.TypeFor[TestWriter]()] = reflect.TypeFor[*TestService]()
+
+	err := c.Build()
+	if !errors.Is(err, ErrDoesNotImplement) {
+		t.Fatalf("Build() error = %v, want %v", err, ErrDoesNotImplement)
+	}
+
+	for _, name := range []string{"TestWriter", "TestService"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Build() error = %q, want it to name %s", err, name)
+		}
+	}
+
+	delete(c.bindings, reflect.TypeFor[TestWriter]())
+
+	// An instance that does not match the type it is registered under.
+	c.providers[reflect.TypeFor[*TestStore]()] = &TestService{}
+
+	if err := c.Build(); !errors.Is(err, ErrDoesNotImplement) || !strings.Contains(err.Error(), "instance *goinject.TestService") {
+		t.Errorf("Build() error = %v, want the mismatched instance reported", err)
+	}
+}
+
+func TestContainer_BuildSkipsScoped(t *testing.T) {
+	c := New()
+	calls := 0
+
+	if err := c.RegisterScoped(func() *TestService {
+		calls++
+		return &TestService{}
+	}); err != nil {
+		t.Fatalf("RegisterScoped() unexpected error = %v", err)
+	}
+
+	if order, err := c.BuildOrder(); err != nil || len(order) != 0 {
+		t.Errorf("BuildOrder() got = %v, %v, want no scoped type", order, err)
+	}
+
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build() unexpected error = %v", err)
+	}
+
+	if calls != 0 {
+		t.Errorf("Build() ran the scoped constructor %d times, want 0", calls)
+	}
+
+	MustGet[TestService](c)
+	MustGet[TestService](c)
+
+	if calls != 1 {
+		t.Errorf("constructor ran %d times after Build and Get, want 1", calls)
+	}
+}
@@ -0,0 +1,80 @@
+package goinject
+
+import (
+	"context"
+)
+
+// RegisterGoroutineLocal registers a factory that builds one instance per goroutine, on the
+// first resolution from that goroutine, for services such as per-worker buffer pools.
+//
+// Goroutines are told apart by their runtime id, which Go does not expose officially and which
+// is read from the goroutine's stack header. A goroutine started by a service gets its own
+// instances, and instances are not released when their goroutine exits: a worker must call
+// ClearGoroutineLocal before it returns. Goroutine-local instances are neither built by Build
+// nor disposed on Close.
+//
+// Example:
+//
+//	container.RegisterGoroutineLocal(func() *BufferPool {
+//	    return NewBufferPool()
+//	})
+//
+//	go func() {
+//	    defer container.ClearGoroutineLocal()
+//	    pool := goinject.MustGet[BufferPool](container) // this worker's own pool
+//	}()
+func (c *Container) RegisterGoroutineLocal(factory any) error {
+
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	f.lifetime = GoroutineLocal
+
+	return c.addFactory(typeof, f)
+}
+
+// ClearGoroutineLocal drops the goroutine-local instances of the calling goroutine,
+// which are built anew on their next resolution from it.
+//
+// Example:
+//
+//	defer container.ClearGoroutineLocal()
+func (c *Container) ClearGoroutineLocal() {
+
+	gid := goroutineID()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, factory := range c.factories {
+		if factory.lifetime == GoroutineLocal {
+			factory.locals.Delete(gid)
+		}
+	}
+}
+
+// local returns the instance the factory built for the calling goroutine, building it on first use.
+// A goroutine only ever builds its own instance, so no lock is needed.
+func (c *Container) local(ctx context.Context, typeof typeof, f *factory) (any, error) {
+
+	gid := goroutineID()
+
+	if instance, ok := f.locals.Load(gid); ok {
+		return instance, nil
+	}
+
+	instance, err := c.run(ctx, typeof, f)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f.locals.Store(gid, instance)
+
+	return instance, nil
+}
@@ -0,0 +1,75 @@
+package goinject
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestContainer_RegisterGoroutineLocal(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterGoroutineLocal(func() *TestService {
+		return &TestService{}
+	}); err != nil {
+		t.Fatalf("RegisterGoroutineLocal() unexpected error = %v", err)
+	}
+
+	resolve := func() (*TestService, *TestService) {
+		return MustGet[TestService](c), MustGet[TestService](c)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results [2][2]*TestService
+	)
+
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i][0], results[i][1] = resolve()
+		}()
+	}
+	wg.Wait()
+
+	for i, r := range results {
+		if r[0] != r[1] {
+			t.Errorf("goroutine %d got %p and %p, want one shared instance", i, r[0], r[1])
+		}
+	}
+
+	if results[0][0] == results[1][0] {
+		t.Errorf("goroutines got the same instance %p, want distinct ones", results[0][0])
+	}
+
+	first, _ := resolve()
+
+	c.ClearGoroutineLocal()
+
+	if again, _ := resolve(); again == first {
+		t.Error("ClearGoroutineLocal() should drop the instance of the calling goroutine")
+	}
+}
+
+func TestContainer_RegisterGoroutineLocal_Cycle(t *testing.T) {
+	c := New()
+
+	var inner error
+	if err := c.RegisterGoroutineLocal(func() *TestService {
+		_, inner = Get[TestService](c)
+		return &TestService{}
+	}); err != nil {
+		t.Fatalf("RegisterGoroutineLocal() unexpected error = %v", err)
+	}
+
+	first := MustGet[TestService](c)
+
+	if !errors.Is(inner, ErrCircularDependency) {
+		t.Errorf("nested Get[T]() error = %v, want %v", inner, ErrCircularDependency)
+	}
+
+	if again := MustGet[TestService](c); again != first {
+		t.Errorf("Get[T]() got = %p, want the goroutine's instance %p", again, first)
+	}
+}
@@ -0,0 +1,339 @@
+package goinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// namedKey identifies a named registration: the same type may be registered under many names.
+type namedKey struct {
+	name   string
+	typeof typeof
+}
+
+// namedFactory is a constructor that builds one instance per name it is resolved under.
+type namedFactory struct {
+	call func(name string) any
+	mu   sync.Mutex
+}
+
+// RegisterNamed registers a singleton instance under a name.
+// Named registrations are separate from unnamed ones and are resolved with GetNamed.
+//
+// Example:
+//
+//	container.RegisterNamed("primary", &DB{DSN: "postgres://primary"})
+//	container.RegisterNamed("replica", &DB{DSN: "postgres://replica"})
+func (c *Container) RegisterNamed(name string, service any) error {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	service, err = c.registering(typeof, service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.named[namedKey{name, typeof}] = service
+
+	return nil
+}
+
+// RegisterNamedFactory registers a factory that receives the name it is resolved under.
+// It answers GetNamed for any name without a registered instance, building the instance
+// once per name. The factory must be a func(string) returning a pointer.
+//
+// Example:
+//
+//	container.RegisterNamedFactory(func(name string) *Worker {
+//	    return &Worker{Queue: name}
+//	})
+//	var worker Worker
+//	container.GetNamed("emails", &worker)
+func (c *Container) RegisterNamedFactory(factory any) error {
+
+	factoryValue := reflect.ValueOf(factory)
+
+	factoryType := factoryValue.Type()
+	{
+		if factoryType.Kind() != reflect.Func {
+			return fmt.Errorf("%w, got %s", ErrFactoryMustBeAFunction, c.name(factoryType))
+		}
+
+		if factoryType.NumIn() != 1 || factoryType.In(0).Kind() != reflect.String {
+			return fmt.Errorf("%w other than the name, got %s", ErrFactoryMustTakeNoArguments, c.name(factoryType))
+		}
+
+		if factoryType.NumOut() != 1 {
+			return fmt.Errorf("%w, got %s", ErrFactoryMustReturnOneValue, c.name(factoryType))
+		}
+	}
+
+	typeof := factoryType.Out(0)
+
+	if typeof.Kind() != reflect.Ptr {
+		return fmt.Errorf("%w, got %s", ErrOutputMustBeAPointer, c.name(factoryType))
+	}
+
+	nameType := factoryType.In(0)
+
+	f := &namedFactory{call: func(name string) any {
+		return factoryValue.Call([]reflect.Value{reflect.ValueOf(name).Convert(nameType)})[0].Interface()
+	}}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	c.namedFactories[typeof] = f
+
+	return nil
+}
+
+// ProvideNamed registers a constructor, like Provide, under a name.
+// The instance is built on the first GetNamed for the name and reused afterwards.
+// Like named instances, it is not materialized under its type and not disposed on Close.
+//
+// Example:
+//
+//	container.ProvideNamed("primary", func(cfg *Config) *DB {
+//	    return Open(cfg.PrimaryDSN)
+//	})
+func (c *Container) ProvideNamed(name string, ctor any) error {
+
+	typeof, factory, err := c.parseConstructor(ctor)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return err
+	}
+
+	factory.named = true
+	c.hooked(typeof, factory)
+	c.namedCtors[namedKey{name, typeof}] = factory
+
+	return nil
+}
+
+// ProvideMap registers every constructor of the map like ProvideNamed, under its key.
+// Every valid constructor is registered; the errors of the invalid ones are joined
+// and reported with their key.
+//
+// Example:
+//
+//	err := container.ProvideMap(map[string]any{
+//	    "emails": NewEmailWorker,
+//	    "images": NewImageWorker,
+//	})
+func (c *Container) ProvideMap(ctors map[string]any) error {
+
+	var errs []error
+
+	for _, name := range slices.Sorted(maps.Keys(ctors)) {
+		if err := c.ProvideNamed(name, ctors[name]); err != nil {
+			errs = append(errs, fmt.Errorf("constructor %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetNamed retrieves the service registered under the name for the type of out.
+// It returns an error matching ErrServiceNotFound if there is neither an instance
+// registered under the name nor a named factory for the type.
+//
+// Example:
+//
+//	var db DB
+//	_, err := container.GetNamed("replica", &db)
+func (c *Container) GetNamed(name string, out any) (any, error) {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := c.resolveNamed(namedKey{name, typeof})
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if typeof.Kind() == reflect.Interface {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(service))
+	}
+
+	return service, nil
+}
+
+// GetNamedMap returns every named registration of *T keyed by its name, building the
+// instances of named constructors that were not built yet. The unnamed registration of *T
+// is not part of the map, and neither are names a named factory has not been resolved under.
+// Instances that fail to build are left out.
+//
+// Example:
+//
+//	for route, handler := range goinject.GetNamedMap[Handler](container) {
+//	    mux.Handle(route, handler)
+//	}
+func GetNamedMap[T any](c *Container) map[string]*T {
+
+	typeof := reflect.TypeFor[*T]()
+
+	var keys []namedKey
+
+	c.mu.RLock()
+	for key := range c.named {
+		if key.typeof == typeof {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.namedCtors {
+		if _, ok := c.named[key]; !ok && key.typeof == typeof {
+			keys = append(keys, key)
+		}
+	}
+	c.mu.RUnlock()
+
+	services := make(map[string]*T, len(keys))
+
+	for _, key := range keys {
+		service, err := c.resolveNamed(key)
+		{
+			if err != nil {
+				continue
+			}
+		}
+
+		services[key.name] = service.(*T)
+	}
+
+	return services
+}
+
+// GetAllNamed returns every named instance that exists, whatever its type, keyed by name:
+// the registered ones and those already built by named factories and constructors. Nothing is
+// built. A name used by several types is disambiguated as "name/type" for each of them,
+// the type rendered like in error messages.
+//
+// Example:
+//
+//	for name, service := range container.GetAllNamed() {
+//	    if handler, ok := service.(Handler); ok {
+//	        dispatch[name] = handler
+//	    }
+//	}
+func (c *Container) GetAllNamed() map[string]any {
+
+	c.mu.RLock()
+
+	instances := maps.Clone(c.named)
+	for key, ctor := range c.namedCtors {
+		if instance, ok := ctor.cached(); ok {
+			instances[key] = instance
+		}
+	}
+
+	c.mu.RUnlock()
+
+	types := make(map[string]int, len(instances))
+	for key := range instances {
+		types[key.name]++
+	}
+
+	services := make(map[string]any, len(instances))
+
+	for key, instance := range instances {
+		if types[key.name] > 1 {
+			services[key.name+"/"+c.name(key.typeof)] = instance
+		} else {
+			services[key.name] = instance
+		}
+	}
+
+	return services
+}
+
+// resolveNamed returns the instance registered under the key, building it from
+// the named factory for its type on first use.
+func (c *Container) resolveNamed(key namedKey) (any, error) {
+
+	c.mu.RLock()
+	service, ok := c.named[key]
+	ctor := c.namedCtors[key]
+	factory := c.namedFactories[key.typeof]
+	c.mu.RUnlock()
+
+	if c.closed.Load() {
+		return nil, ErrContainerClosed
+	}
+
+	if ok {
+		return service, nil
+	}
+
+	if ctor != nil {
+		return c.build(context.Background(), key.typeof, ctor)
+	}
+
+	if factory == nil {
+		return nil, fmt.Errorf("%w: %s named %q", ErrServiceNotFound, c.name(key.typeof), key.name)
+	}
+
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	// Another goroutine may have built the same name meanwhile.
+	c.mu.RLock()
+	service, ok = c.named[key]
+	c.mu.RUnlock()
+
+	if ok {
+		return service, nil
+	}
+
+	service, err := c.registering(key.typeof, factory.call(key.name))
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.named[key] = service
+
+	return service, nil
+}
@@ -0,0 +1,236 @@
+package goinject
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+type TestWorker struct {
+	Name string
+}
+
+func TestContainer_RegisterNamed(t *testing.T) {
+	c := New()
+	primary := &TestService{Name: "primary"}
+	replica := &TestService{Name: "replica"}
+
+	if err := c.RegisterNamed("primary", primary); err != nil {
+		t.Fatalf("RegisterNamed() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterNamed("replica", replica); err != nil {
+		t.Fatalf("RegisterNamed() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want *TestService
+	}{
+		{"primary", primary},
+		{"replica", replica},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetNamed(tt.name, &TestService{})
+			if err != nil || got != tt.want {
+				t.Errorf("GetNamed() got = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := c.GetNamed("missing", &TestService{}); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("GetNamed() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	// Named registrations do not answer unnamed resolutions.
+	if _, err := Get[TestService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestContainer_RegisterNamedFactory(t *testing.T) {
+	c := New()
+	builds := 0
+
+	if err := c.RegisterNamedFactory(func(name string) *TestWorker {
+		builds++
+		return &TestWorker{Name: name}
+	}); err != nil {
+		t.Fatalf("RegisterNamedFactory() unexpected error = %v", err)
+	}
+
+	w1, err := c.GetNamed("w1", &TestWorker{})
+	if err != nil {
+		t.Fatalf("GetNamed() unexpected error = %v", err)
+	}
+
+	w2, err := c.GetNamed("w2", &TestWorker{})
+	if err != nil {
+		t.Fatalf("GetNamed() unexpected error = %v", err)
+	}
+
+	if w1.(*TestWorker).Name != "w1" || w2.(*TestWorker).Name != "w2" {
+		t.Errorf("GetNamed() got = %v, %v, want workers named w1 and w2", w1, w2)
+	}
+
+	again, err := c.GetNamed("w1", &TestWorker{})
+	if err != nil || again != w1 || builds != 2 {
+		t.Errorf("GetNamed() got = %v, %v after %d builds, want the cached %v", again, err, builds, w1)
+	}
+
+	if err := c.RegisterNamedFactory(func() *TestWorker { return nil }); !errors.Is(err, ErrFactoryMustTakeNoArguments) {
+		t.Errorf("RegisterNamedFactory() error = %v, want %v", err, ErrFactoryMustTakeNoArguments)
+	}
+}
+
+func TestContainer_ProvideMap(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "shared"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.ProvideMap(map[string]any{
+		"emails": func(service *TestService) *TestWorker {
+			return &TestWorker{Name: "emails:" + service.Name}
+		},
+		"images": func() *TestWorker {
+			return &TestWorker{Name: "images"}
+		},
+	}); err != nil {
+		t.Fatalf("ProvideMap() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"emails", "emails:shared"},
+		{"images", "images"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetNamed(tt.name, &TestWorker{})
+			if err != nil {
+				t.Fatalf("GetNamed() unexpected error = %v", err)
+			}
+
+			if got.(*TestWorker).Name != tt.want {
+				t.Errorf("GetNamed() got = %v, want %v", got.(*TestWorker).Name, tt.want)
+			}
+
+			if again, _ := c.GetNamed(tt.name, &TestWorker{}); again != got {
+				t.Errorf("GetNamed() got = %p, want the cached %p", again, got)
+			}
+		})
+	}
+
+	err := c.ProvideMap(map[string]any{"broken": 42, "valid": func() *TestWorker { return &TestWorker{} }})
+	if !errors.Is(err, ErrFactoryMustBeAFunction) || !strings.Contains(err.Error(), `constructor "broken"`) {
+		t.Errorf("ProvideMap() error = %v, want the key of the invalid constructor", err)
+	}
+}
+
+func TestGetNamedMap(t *testing.T) {
+	c := New()
+
+	users, orders := &TestWorker{Name: "users"}, &TestWorker{Name: "orders"}
+
+	if err := c.RegisterNamed("/users", users); err != nil {
+		t.Fatalf("RegisterNamed() unexpected error = %v", err)
+	}
+	if err := c.RegisterNamed("/orders", orders); err != nil {
+		t.Fatalf("RegisterNamed() unexpected error = %v", err)
+	}
+	if err := c.ProvideNamed("/health", func() *TestWorker { return &TestWorker{Name: "health"} }); err != nil {
+		t.Fatalf("ProvideNamed() unexpected error = %v", err)
+	}
+	if err := c.Register(&TestWorker{Name: "unnamed"}); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+	if err := c.RegisterNamed("/users", &TestService{}); err != nil {
+		t.Fatalf("RegisterNamed() unexpected error = %v", err)
+	}
+
+	got := GetNamedMap[TestWorker](c)
+
+	if len(got) != 3 || got["/users"] != users || got["/orders"] != orders || got["/health"].Name != "health" {
+		t.Errorf("GetNamedMap[T]() got = %v, want /users, /orders and /health", got)
+	}
+}
+
+func TestContainer_GetAllNamed(t *testing.T) {
+	c := New()
+
+	emails, primary := &TestWorker{Name: "emails"}, &TestService{Name: "primary"}
+	sharedWorker, sharedService := &TestWorker{Name: "shared"}, &TestService{Name: "shared"}
+
+	for _, r := range []struct {
+		name    string
+		service any
+	}{
+		{"emails", emails},
+		{"primary", primary},
+		{"shared", sharedWorker},
+		{"shared", sharedService},
+	} {
+		if err := c.RegisterNamed(r.name, r.service); err != nil {
+			t.Fatalf("RegisterNamed() unexpected error = %v", err)
+		}
+	}
+
+	built := false
+	if err := c.ProvideNamed("lazy", func() *TestWorker {
+		built = true
+		return &TestWorker{Name: "lazy"}
+	}); err != nil {
+		t.Fatalf("ProvideNamed() unexpected error = %v", err)
+	}
+
+	want := map[string]any{
+		"emails":                       emails,
+		"primary":                      primary,
+		"shared/*goinject.TestWorker":  sharedWorker,
+		"shared/*goinject.TestService": sharedService,
+	}
+
+	if got := c.GetAllNamed(); !maps.Equal(got, want) || built {
+		t.Errorf("GetAllNamed() got = %v, built = %v, want %v without building", got, built, want)
+	}
+
+	var lazy TestWorker
+	if _, err := c.GetNamed("lazy", &lazy); err != nil {
+		t.Fatalf("GetNamed() unexpected error = %v", err)
+	}
+
+	if got := c.GetAllNamed()["lazy"]; got == nil {
+		t.Error("GetAllNamed() should include named instances once built")
+	}
+}
+
+func TestContainer_ProvideNamedNotMaterialized(t *testing.T) {
+	c := New()
+
+	if err := c.ProvideNamed("a", func() *TestService {
+		return &TestService{Name: "a"}
+	}); err != nil {
+		t.Fatalf("ProvideNamed() unexpected error = %v", err)
+	}
+
+	var service TestService
+	if _, err := c.GetNamed("a", &service); err != nil {
+		t.Fatalf("GetNamed() unexpected error = %v", err)
+	}
+
+	if got := c.MaterializedTypes(); len(got) != 0 {
+		t.Errorf("MaterializedTypes() got = %v, want the named instance left out", got)
+	}
+
+	if _, err := Get[TestService](c); err != ErrServiceNotFound {
+		t.Errorf("Get[TestService]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
@@ -0,0 +1,337 @@
+package goinject
+
+import (
+	"reflect"
+	"time"
+)
+
+// Option configures a Container created with New.
+type Option func(*Container)
+
+// Invoker surrounds a factory invocation for the given type.
+// It must call next to run the factory, or return without calling it to short-circuit.
+type Invoker func(t reflect.Type, next func() (any, error)) (any, error)
+
+// WithInvoker adds a middleware that surrounds every factory invocation.
+// Multiple invokers chain in registration order, the first one being the outermost.
+// An instance returned by an invoker that is not of the registered type fails the
+// resolution with ErrInstanceType.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithInvoker(
+//	    func(t reflect.Type, next func() (any, error)) (any, error) {
+//	        start := time.Now()
+//	        defer func() { log.Printf("built %s in %s", t, time.Since(start)) }()
+//	        return next()
+//	    },
+//	))
+func WithInvoker(invoker Invoker) Option {
+	return func(c *Container) {
+		c.invokers = append(c.invokers, invoker)
+	}
+}
+
+// WithAutoPointer makes Register accept struct values and other non-pointer values.
+// The value is copied and a pointer to the copy is registered under *T, so later
+// changes to the original value are not visible through the container.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithAutoPointer())
+//	container.Register(Config{Env: "prod"})
+//	config, _ := goinject.Get[Config](container) // *Config pointing to a copy
+func WithAutoPointer() Option {
+	return func(c *Container) {
+		c.autoPointer = true
+	}
+}
+
+// WithoutLocking makes the container skip its internal lock, which saves the locking
+// overhead when a single goroutine does all the wiring and resolution, as in CLI tools
+// or code generators.
+//
+// The container is then UNSAFE FOR CONCURRENT USE: registering or resolving from several
+// goroutines at once corrupts it. Never share such a container between goroutines.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithoutLocking())
+func WithoutLocking() Option {
+	return func(c *Container) {
+		c.mu = noLock{}
+	}
+}
+
+// WithCopyOnWrite makes Freeze publish the registrations to an immutable snapshot,
+// so resolutions after Freeze no longer take the container lock.
+// Registration before Freeze is unaffected.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithCopyOnWrite())
+//	container.Register(&User{ID: 1, Name: "John"})
+//	container.Freeze() // reads are lock-free from here on
+func WithCopyOnWrite() Option {
+	return func(c *Container) {
+		c.copyOnWrite = true
+	}
+}
+
+// WithStructuralResolution lets an interface request without an exact registration
+// resolve to the single registered type that implements the interface.
+// If several registered types implement it, resolution fails with ErrAmbiguousResolution.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithStructuralResolution())
+//	container.Register(&bytes.Buffer{})
+//	writer, _ := goinject.Get[io.Writer](container) // the registered *bytes.Buffer
+func WithStructuralResolution() Option {
+	return func(c *Container) {
+		c.structural = true
+	}
+}
+
+// WithOnMissing installs a last-chance hook that runs when a requested type has no registration.
+// If the hook returns an instance, it is registered under the requested type and returned,
+// even once the container is frozen; otherwise the resolution fails with ErrServiceNotFound.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithOnMissing(func(t reflect.Type) (any, bool) {
+//	    return plugins.Load(t)
+//	}))
+func WithOnMissing(hook func(t reflect.Type) (any, bool)) Option {
+	return func(c *Container) {
+		c.onMissing = hook
+	}
+}
+
+// WithFallbackProvider bridges the container to an external service locator: the provider
+// is consulted when a requested type has no registration, before the resolution fails with
+// ErrServiceNotFound. Unlike WithOnMissing, what it returns is not registered, so the
+// provider is asked again on every resolution of the type.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithFallbackProvider(func(t reflect.Type) (any, bool) {
+//	    return legacyLocator.Lookup(t)
+//	}))
+func WithFallbackProvider(provider func(t reflect.Type) (any, bool)) Option {
+	return func(c *Container) {
+		c.fallback = provider
+	}
+}
+
+// WithReflectiveDefault changes what happens when an unregistered pointer to a struct is
+// requested: instead of failing with ErrServiceNotFound, the container registers a pointer
+// to the zero value of the struct and returns it, even once the container is frozen, so simple
+// structs without dependencies need no registration. Interfaces and other types still fail as usual.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithReflectiveDefault())
+//	stats, _ := goinject.Get[Stats](container) // &Stats{}, shared from now on
+func WithReflectiveDefault() Option {
+	return func(c *Container) {
+		c.reflectiveDefault = true
+	}
+}
+
+// WithLogger installs a structured logger for registration and resolution events.
+// Events carry the type name as "type" and, for resolutions, the elapsed time as "duration";
+// failed resolutions are logged at the "error" level with the "error" key.
+// Without a logger nothing is logged and no timing is taken.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithLogger(func(level, msg string, kv ...any) {
+//	    slog.Debug(msg, append([]any{"level", level}, kv...)...)
+//	}))
+func WithLogger(logger func(level, msg string, kv ...any)) Option {
+	return func(c *Container) {
+		c.logger = logger
+	}
+}
+
+// WithActiveProfiles activates the given profiles for RegisterProfile.
+// Registrations made for any other profile are not resolvable.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithActiveProfiles("prod", "eu"))
+func WithActiveProfiles(profiles ...string) Option {
+	return func(c *Container) {
+		if c.profiles == nil {
+			c.profiles = make(map[string]bool, len(profiles))
+		}
+
+		for _, profile := range profiles {
+			c.profiles[profile] = true
+		}
+	}
+}
+
+// WithMaxCachedInstances bounds the number of live instances of RegisterCached factories.
+// Past the bound, the least recently resolved instance is evicted and disposed if it
+// implements Disposable; it is rebuilt on its next resolution. A bound of zero or less
+// keeps every instance.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithMaxCachedInstances(64))
+func WithMaxCachedInstances(n int) Option {
+	return func(c *Container) {
+		c.maxCached = n
+	}
+}
+
+// WithPostResolve adds a hook that receives every instance built by a factory and returns
+// the instance to use instead, or the same one. The returned instance is what singletons
+// cache; one that is not of the registered type fails the resolution with ErrInstanceType.
+// Registered instances are not passed to the hook. Hooks run in the order they are given.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithPostResolve(func(t reflect.Type, instance any) any {
+//	    if repo, ok := instance.(UserRepository); ok {
+//	        return &tracingRepository{repo}
+//	    }
+//	    return instance
+//	}))
+func WithPostResolve(hook func(t reflect.Type, instance any) any) Option {
+	return func(c *Container) {
+		c.postResolve = append(c.postResolve, hook)
+	}
+}
+
+// WithRegistrationHook adds a hook that validates or wraps every registration: every instance
+// given to Register or any other registration function, named ones included, and every instance
+// built by a factory or constructor, as it is built. Only keyed factories are not passed to it.
+// The hook returns the instance to register instead, which must be of the same type, or the
+// same one; an instance of another type is rejected like an error. An error aborts the
+// registration, or fails the resolution for a factory result.
+// Hooks run in the order they are given.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithRegistrationHook(func(t reflect.Type, instance any) (any, error) {
+//	    if _, ok := instance.(Service); !ok {
+//	        return nil, fmt.Errorf("%s does not implement Service", t)
+//	    }
+//	    return instance, nil
+//	}))
+func WithRegistrationHook(hook func(t reflect.Type, instance any) (any, error)) Option {
+	return func(c *Container) {
+		c.onRegister = append(c.onRegister, hook)
+	}
+}
+
+// WithResolveMetric installs a callback invoked after every resolution, including the
+// resolutions of dependencies, with the type, how long it took, whether it was served from
+// a registered or cached instance rather than built, and the error if it failed. It is a
+// lighter alternative to Stats for forwarding to a metrics system, and it is called without
+// holding the container lock.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithResolveMetric(
+//	    func(t reflect.Type, d time.Duration, cached bool, err error) {
+//	        resolveSeconds.WithLabelValues(t.String(), strconv.FormatBool(cached)).Observe(d.Seconds())
+//	    },
+//	))
+func WithResolveMetric(metric func(t reflect.Type, d time.Duration, cached bool, err error)) Option {
+	return func(c *Container) {
+		c.onResolve = metric
+	}
+}
+
+// WithStats makes the container count the resolutions and the factory calls of every type,
+// as reported by Stats and WritePrometheus. Without it the resolution path keeps no counters.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithStats())
+func WithStats() Option {
+	return func(c *Container) {
+		c.stats = true
+	}
+}
+
+// WithStrictRegistration makes registering a factory for a type that already has an
+// instance fail with ErrInstanceRegistered. By default the conflict is only logged as
+// a warning, since the instance keeps precedence over the factory.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithStrictRegistration())
+func WithStrictRegistration() Option {
+	return func(c *Container) {
+		c.strict = true
+	}
+}
+
+// WithStrictInterfaces makes resolving an interface without an explicit registration fail
+// with ErrNoBinding, which also matches ErrServiceNotFound, even if a registered type
+// implements it and WithStructuralResolution is set. Interfaces must then be bound with
+// RegisterAs, Bind or a factory registered under the interface. GetOk, GetOrNil and
+// GetOrElse still treat such an interface as absent. Collections are not affected: GetSlice,
+// GetInto and variadic constructor parameters still gather every registration implementing
+// the interface, since they ask for all implementations rather than for a single binding.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithStrictInterfaces())
+//	goinject.RegisterAs[io.Writer](container, &bytes.Buffer{})
+func WithStrictInterfaces() Option {
+	return func(c *Container) {
+		c.strictInterfaces = true
+	}
+}
+
+// WithTypeNameFunc sets how types are rendered in error messages, the panics of the Must
+// helpers, log output and metadata snapshots. By default a type is rendered with its String
+// method. Errors returned by factories, hooks and other user code are passed on as they are.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithTypeNameFunc(func(t reflect.Type) string {
+//	    return strings.TrimPrefix(t.String(), "*")
+//	}))
+func WithTypeNameFunc(name func(reflect.Type) string) Option {
+	return func(c *Container) {
+		c.typeName = name
+	}
+}
+
+// WithGracefulMissing makes constructor injection pass a typed nil for a missing pointer
+// dependency instead of failing the resolution, so that a partially wired container
+// still boots in development. Only a dependency that is not registered at all is passed
+// as nil; one that is registered but fails on a missing dependency of its own still fails.
+// Each dependency passed as nil is reported to report, which may be nil, together with
+// the type whose constructor requested it.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithGracefulMissing(func(dep, of reflect.Type) {
+//	    log.Printf("%s of %s is missing, passing nil", dep, of)
+//	}))
+func WithGracefulMissing(report func(dep, of reflect.Type)) Option {
+	return func(c *Container) {
+		c.graceful = true
+		c.onGraceful = report
+	}
+}
+
+// WithRejectNilFactoryResults makes a factory that returns a nil pointer fail with
+// ErrNilFactoryResult instead of caching the nil instance.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithRejectNilFactoryResults())
+func WithRejectNilFactoryResults() Option {
+	return func(c *Container) {
+		c.rejectNil = true
+	}
+}
@@ -0,0 +1,1003 @@
+package goinject
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithInvoker(t *testing.T) {
+	var calls []string
+
+	c := New(
+		WithInvoker(func(typeof reflect.Type, next func() (any, error)) (any, error) {
+			calls = append(calls, "outer:"+typeof.String())
+			return next()
+		}),
+		WithInvoker(func(typeof reflect.Type, next func() (any, error)) (any, error) {
+			calls = append(calls, "inner:"+typeof.String())
+			return next()
+		}),
+	)
+
+	if err := c.RegisterFactory(func() *TestService {
+		calls = append(calls, "factory")
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	result, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if result.Name != "test" {
+		t.Errorf("Get[T]() got = %v, want %v", result.Name, "test")
+	}
+
+	want := []string{"outer:*goinject.TestService", "inner:*goinject.TestService", "factory"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("invokers got = %v, want %v", calls, want)
+	}
+}
+
+func TestWithInvoker_ShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+	called := false
+
+	c := New(WithInvoker(func(reflect.Type, func() (any, error)) (any, error) {
+		return nil, errDenied
+	}))
+
+	if err := c.RegisterFactory(func() *TestService {
+		called = true
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, errDenied) {
+		t.Errorf("Get[T]() error = %v, want %v", err, errDenied)
+	}
+
+	if called {
+		t.Error("factory should not run when the invoker short-circuits")
+	}
+}
+
+func TestWithInvoker_WrongType(t *testing.T) {
+	c := New(WithInvoker(func(typeof reflect.Type, next func() (any, error)) (any, error) {
+		if typeof == reflect.TypeFor[*TestService]() {
+			return &AnotherService{}, nil
+		}
+		return next()
+	}))
+
+	if err := c.Provide(func() *TestService { return &TestService{} }); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if _, err := Get[TestRepository](c); !errors.Is(err, ErrInstanceType) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrInstanceType)
+	}
+}
+
+func TestWithAutoPointer(t *testing.T) {
+	c := New(WithAutoPointer())
+	service := TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	// The container holds a copy, so the original stays independent.
+	service.Name = "changed"
+
+	result, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if result.Name != "test" {
+		t.Errorf("Get[T]() got = %v, want %v", result.Name, "test")
+	}
+
+	if err := New().Register(service); !errors.Is(err, ErrOutputMustBeAPointer) {
+		t.Errorf("Register() without WithAutoPointer error = %v, want %v", err, ErrOutputMustBeAPointer)
+	}
+}
+
+func TestWithCopyOnWrite(t *testing.T) {
+	c := New(WithCopyOnWrite())
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	var builds atomic.Int32
+	if err := c.RegisterFactory(func() *AnotherService {
+		builds.Add(1)
+		return &AnotherService{ID: 1}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	c.Freeze()
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				if result, err := Get[TestService](c); err != nil || result != service {
+					t.Errorf("Get[TestService]() got = %v, %v, want %v", result, err, service)
+					return
+				}
+
+				if result, err := Get[AnotherService](c); err != nil || result.ID != 1 {
+					t.Errorf("Get[AnotherService]() got = %v, %v", result, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := builds.Load(); got != 1 {
+		t.Errorf("factory ran %d times, want 1", got)
+	}
+
+	if err := c.Register(&TestService{}); !errors.Is(err, ErrContainerFrozen) {
+		t.Errorf("Register() after Freeze error = %v, want %v", err, ErrContainerFrozen)
+	}
+}
+
+func BenchmarkGet_Frozen(b *testing.B) {
+	benchmarkFrozenGet(b, New())
+}
+
+func BenchmarkGet_FrozenCopyOnWrite(b *testing.B) {
+	benchmarkFrozenGet(b, New(WithCopyOnWrite()))
+}
+
+func BenchmarkGet_Locked(b *testing.B) {
+	benchmarkGet(b, New())
+}
+
+func BenchmarkGet_WithoutLocking(b *testing.B) {
+	benchmarkGet(b, New(WithoutLocking()))
+}
+
+func benchmarkGet(b *testing.B, c *Container) {
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		b.Fatalf("failed to register service: %v", err)
+	}
+
+	out := &TestService{}
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := c.Get(out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkFrozenGet(b *testing.B, c *Container) {
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		b.Fatalf("failed to register service: %v", err)
+	}
+
+	c.Freeze()
+
+	out := &TestService{}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Get(out); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestWithStructuralResolution(t *testing.T) {
+	c := New(WithStructuralResolution())
+	buffer := &bytes.Buffer{}
+
+	if err := c.Register(buffer); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	writer, err := Get[io.Writer](c)
+	if err != nil {
+		t.Fatalf("Get[io.Writer]() unexpected error = %v", err)
+	}
+
+	if *writer != io.Writer(buffer) {
+		t.Errorf("Get[io.Writer]() got = %v, want %v", *writer, buffer)
+	}
+
+	if _, err := Get[io.Writer](New()); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[io.Writer]() without WithStructuralResolution error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestWithStructuralResolution_Ambiguous(t *testing.T) {
+	c := New(WithStructuralResolution())
+
+	if err := c.Register(&bytes.Buffer{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Register(&strings.Builder{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if _, err := Get[io.Writer](c); !errors.Is(err, ErrAmbiguousResolution) {
+		t.Errorf("Get[io.Writer]() error = %v, want %v", err, ErrAmbiguousResolution)
+	}
+}
+
+func TestWithOnMissing_Frozen(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"locked", nil},
+		{"copy on write", []Option{WithCopyOnWrite()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			misses := 0
+
+			c := New(append(tt.opts, WithReflectiveDefault(), WithOnMissing(func(typeof reflect.Type) (any, bool) {
+				if typeof != reflect.TypeFor[*TestService]() {
+					return nil, false
+				}
+
+				misses++
+				return &TestService{Name: "on-missing"}, true
+			}))...)
+
+			c.Freeze()
+
+			for range 2 {
+				if service, err := Get[TestService](c); err != nil || service.Name != "on-missing" {
+					t.Fatalf("Get[TestService]() after Freeze got = %v, %v", service, err)
+				}
+
+				if _, err := Get[AnotherService](c); err != nil {
+					t.Fatalf("Get[AnotherService]() after Freeze unexpected error = %v", err)
+				}
+			}
+
+			if misses != 1 {
+				t.Errorf("on-missing hook ran %d times, want the instance registered once", misses)
+			}
+
+			if MustGet[AnotherService](c) != MustGet[AnotherService](c) {
+				t.Error("reflective default should be shared after Freeze")
+			}
+
+			if err := c.Register(&TestRepository{}); !errors.Is(err, ErrContainerFrozen) {
+				t.Errorf("Register() after Freeze error = %v, want %v", err, ErrContainerFrozen)
+			}
+		})
+	}
+}
+
+func TestWithOnMissing(t *testing.T) {
+	var misses []reflect.Type
+
+	c := New(WithOnMissing(func(typeof reflect.Type) (any, bool) {
+		misses = append(misses, typeof)
+
+		if typeof == reflect.TypeOf(&TestService{}) {
+			return &TestService{Name: "loaded"}, true
+		}
+
+		return nil, false
+	}))
+
+	first, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if first.Name != "loaded" {
+		t.Errorf("Get[T]() got = %v, want %v", first.Name, "loaded")
+	}
+
+	// The supplied instance is registered, so the hook is not asked again.
+	second, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if second != first {
+		t.Errorf("Get[T]() got = %p, want %p", second, first)
+	}
+
+	if _, err := Get[AnotherService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if len(misses) != 2 {
+		t.Errorf("hook called %d times, want %d", len(misses), 2)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	type event struct {
+		level, msg, typeof string
+	}
+
+	var events []event
+
+	c := New(WithLogger(func(level, msg string, kv ...any) {
+		e := event{level: level, msg: msg}
+
+		for i := 0; i+1 < len(kv); i += 2 {
+			if kv[i] == "type" {
+				e.typeof = kv[i+1].(string)
+			}
+		}
+
+		events = append(events, e)
+	}))
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if _, err := Get[AnotherService](c); err == nil {
+		t.Fatal("Get[T]() expected an error for a missing service")
+	}
+
+	want := []event{
+		{"debug", "register", "*goinject.TestService"},
+		{"debug", "resolve", "*goinject.TestService"},
+		{"error", "resolve failed", "*goinject.AnotherService"},
+	}
+
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("logged events = %v, want %v", events, want)
+	}
+}
+
+func TestWithRejectNilFactoryResults(t *testing.T) {
+	nilFactory := func() *TestService { return nil }
+
+	permissive := New()
+	if err := permissive.RegisterFactory(nilFactory); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if result, err := Get[TestService](permissive); err != nil || result != nil {
+		t.Errorf("Get[T]() got = %v, %v, want a nil instance", result, err)
+	}
+
+	strict := New(WithRejectNilFactoryResults())
+	if err := strict.RegisterFactory(nilFactory); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	_, err := Get[TestService](strict)
+	if !errors.Is(err, ErrNilFactoryResult) {
+		t.Fatalf("Get[T]() error = %v, want %v", err, ErrNilFactoryResult)
+	}
+
+	if !strings.Contains(err.Error(), "*goinject.TestService") {
+		t.Errorf("Get[T]() error = %v, want it to name the type", err)
+	}
+}
+
+func TestWithPostResolve_WrongType(t *testing.T) {
+	type Wrap struct{ *TestService }
+
+	c := New(WithPostResolve(func(_ reflect.Type, instance any) any {
+		if service, ok := instance.(*TestService); ok {
+			return &Wrap{service}
+		}
+		return instance
+	}))
+
+	if err := c.Provide(func() *TestService { return &TestService{} }); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrInstanceType) {
+		t.Errorf("Get[TestService]() error = %v, want %v", err, ErrInstanceType)
+	}
+
+	if _, err := Get[TestRepository](c); !errors.Is(err, ErrInstanceType) {
+		t.Errorf("Get[TestRepository]() error = %v, want %v", err, ErrInstanceType)
+	}
+
+	var service TestService
+	if err := c.GetValue(&service); !errors.Is(err, ErrInstanceType) {
+		t.Errorf("GetValue() error = %v, want %v", err, ErrInstanceType)
+	}
+}
+
+func TestWithPostResolve(t *testing.T) {
+	var seen []reflect.Type
+	replacement := &TestService{Name: "replacement"}
+
+	c := New(WithPostResolve(func(typeof reflect.Type, instance any) any {
+		seen = append(seen, typeof)
+
+		if _, ok := instance.(*TestService); ok {
+			return replacement
+		}
+
+		return instance
+	}))
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "original"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.RegisterFactory(func() *AnotherService {
+		return &AnotherService{ID: 1}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	for range 2 {
+		if result, err := Get[TestService](c); err != nil || result != replacement {
+			t.Errorf("Get[TestService]() got = %v, %v, want %v", result, err, replacement)
+		}
+	}
+
+	if result, err := Get[AnotherService](c); err != nil || result.ID != 1 {
+		t.Errorf("Get[AnotherService]() got = %v, %v", result, err)
+	}
+
+	// The replacement is cached, so each factory passed through the hook once.
+	want := []reflect.Type{reflect.TypeOf(&TestService{}), reflect.TypeOf(&AnotherService{})}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("hook saw %v, want %v", seen, want)
+	}
+}
+
+func TestWithStrictRegistration(t *testing.T) {
+	instance := &TestService{Name: "instance"}
+	factory := func() *TestService { return &TestService{Name: "factory"} }
+
+	var warnings []string
+	lenient := New(WithLogger(func(level, msg string, kv ...any) {
+		if level == "warn" {
+			warnings = append(warnings, msg)
+		}
+	}))
+
+	if err := lenient.Register(instance); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := lenient.RegisterFactory(factory); err != nil {
+		t.Errorf("RegisterFactory() unexpected error = %v", err)
+	}
+
+	if len(warnings) != 1 {
+		t.Errorf("logged warnings = %v, want one", warnings)
+	}
+
+	if result, _ := Get[TestService](lenient); result != instance {
+		t.Errorf("Get[T]() got = %v, want the instance %v", result, instance)
+	}
+
+	strict := New(WithStrictRegistration())
+
+	if err := strict.Register(instance); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := strict.RegisterFactory(factory); !errors.Is(err, ErrInstanceRegistered) {
+		t.Errorf("RegisterFactory() error = %v, want %v", err, ErrInstanceRegistered)
+	}
+
+	if err := strict.RegisterFactoryOrReplace(factory); err != nil {
+		t.Fatalf("RegisterFactoryOrReplace() unexpected error = %v", err)
+	}
+
+	if result, _ := Get[TestService](strict); result == nil || result.Name != "factory" {
+		t.Errorf("Get[T]() got = %v, want the factory instance", result)
+	}
+}
+
+func TestWithTypeNameFunc(t *testing.T) {
+	short := WithTypeNameFunc(func(typeof reflect.Type) string {
+		return strings.NewReplacer("*", "", "goinject.", "").Replace(typeof.String())
+	})
+
+	newRepository := func(*AnotherService) *TestRepository { return &TestRepository{} }
+
+	c := New(short)
+	if err := c.Provide(newRepository); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	_, err := Get[TestRepository](c)
+	if want := "dependency AnotherService of TestRepository: service not found"; err == nil || err.Error() != want {
+		t.Errorf("Get[T]() error = %v, want %v", err, want)
+	}
+
+	data, err := c.SnapshotMeta()
+	if err != nil {
+		t.Fatalf("SnapshotMeta() unexpected error = %v", err)
+	}
+
+	if !bytes.Contains(data, []byte("TestRepository")) || bytes.Contains(data, []byte("goinject.")) {
+		t.Errorf("SnapshotMeta() got = %q, want the custom type names", data)
+	}
+
+	if _, err := LoadMeta(data, map[string]any{"TestRepository": newRepository}, short); err != nil {
+		t.Errorf("LoadMeta() unexpected error = %v", err)
+	}
+
+	if err := RegisterAs[TestReader](c, &TestService{}); err == nil || err.Error() != "TestService does not implement TestReader" {
+		t.Errorf("RegisterAs[T]() error = %v, want the custom type names", err)
+	}
+
+	if err := RegisterAs[TestService](c, &TestStore{}); err == nil || err.Error() != "TestService is not an interface" {
+		t.Errorf("RegisterAs[T]() error = %v, want the custom type names", err)
+	}
+
+	err = c.RegisterFactoryForTypes(func() *TestService { return &TestService{} }, reflect.TypeFor[TestReader]())
+	if err == nil || err.Error() != "TestService does not implement TestReader" {
+		t.Errorf("RegisterFactoryForTypes() error = %v, want the custom type names", err)
+	}
+
+	if err := c.Provide(func() TestService { return TestService{} }); err == nil || err.Error() != "output must be a pointer, got func() TestService" {
+		t.Errorf("Provide() error = %v, want the custom type names", err)
+	}
+
+	var service TestService
+	if err := GetValue(c, &service); err == nil || !strings.HasPrefix(err.Error(), "GetValue[TestService]") {
+		t.Errorf("GetValue[T]() error = %v, want the custom type names", err)
+	}
+
+	func() {
+		defer func() {
+			r := recover()
+			if p, ok := r.(*MustPanic); !ok || !strings.HasPrefix(p.Error(), "MustGet TestService: ") {
+				t.Errorf("MustGet[T]() panicked with %v, want the custom type names", r)
+			}
+		}()
+
+		MustGet[TestService](c)
+	}()
+}
+
+func TestWithGracefulMissing(t *testing.T) {
+	newRepository := func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}
+
+	strict := New()
+	if err := strict.Provide(newRepository); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	if _, err := Get[TestRepository](strict); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	var missing []reflect.Type
+	graceful := New(WithGracefulMissing(func(dep, of reflect.Type) {
+		missing = append(missing, dep, of)
+	}))
+	if err := graceful.Provide(newRepository); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	repository, err := Get[TestRepository](graceful)
+	if err != nil || repository.Service != nil {
+		t.Fatalf("Get[T]() got = %v, %v, want a repository with a nil service", repository, err)
+	}
+
+	want := []reflect.Type{reflect.TypeFor[*TestService](), reflect.TypeFor[*TestRepository]()}
+	if !slices.Equal(missing, want) {
+		t.Errorf("reported missing %v, want %v", missing, want)
+	}
+
+	// A registered dependency that fails on a miss of its own is not passed as nil.
+	nested := New(WithGracefulMissing(nil))
+	if err := nested.ProvideAll(
+		func(TestReader) *TestService { return &TestService{} },
+		newRepository,
+	); err != nil {
+		t.Fatalf("failed to provide constructors: %v", err)
+	}
+
+	if repository, err := Get[TestRepository](nested); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() got = %v, %v, want %v", repository, err, ErrServiceNotFound)
+	}
+}
+
+func TestWithoutLocking(t *testing.T) {
+	c := New(WithoutLocking())
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func(s *TestService) *TestRepository { return &TestRepository{Service: s} }); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	repository, err := Get[TestRepository](c)
+	if err != nil || repository.Service != service {
+		t.Errorf("Get[T]() got = %v, %v, want a repository of %v", repository, err, service)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() unexpected error = %v", err)
+	}
+}
+
+func TestWithRegistrationHook(t *testing.T) {
+	errNotDisposable := errors.New("not disposable")
+
+	t.Run("rejects", func(t *testing.T) {
+		c := New(WithRegistrationHook(func(_ reflect.Type, instance any) (any, error) {
+			if _, ok := instance.(Disposable); !ok {
+				return nil, errNotDisposable
+			}
+			return instance, nil
+		}))
+
+		if err := c.Register(&TestDisposable{}); err != nil {
+			t.Errorf("Register() unexpected error = %v", err)
+		}
+
+		if err := c.Register(&TestService{}); !errors.Is(err, errNotDisposable) {
+			t.Errorf("Register() error = %v, want %v", err, errNotDisposable)
+		}
+
+		if _, err := Get[TestService](c); !errors.Is(err, ErrServiceNotFound) {
+			t.Errorf("Get[T]() error = %v, a rejected instance should not be registered", err)
+		}
+
+		if err := c.RegisterFactory(func() *AnotherService { return &AnotherService{} }); err != nil {
+			t.Fatalf("failed to register factory: %v", err)
+		}
+
+		if _, err := Get[AnotherService](c); !errors.Is(err, errNotDisposable) {
+			t.Errorf("Get[T]() error = %v, want %v", err, errNotDisposable)
+		}
+	})
+
+	t.Run("wraps", func(t *testing.T) {
+		c := New(WithRegistrationHook(func(_ reflect.Type, instance any) (any, error) {
+			if service, ok := instance.(*TestService); ok {
+				return &TestService{Name: service.Name + " (wrapped)"}, nil
+			}
+			return instance, nil
+		}))
+
+		if err := c.Register(&TestService{Name: "test"}); err != nil {
+			t.Fatalf("failed to register service: %v", err)
+		}
+
+		if result := MustGet[TestService](c); result.Name != "test (wrapped)" {
+			t.Errorf("Get[T]() got = %v, want the wrapped instance", result.Name)
+		}
+	})
+
+	t.Run("rejects another type", func(t *testing.T) {
+		c := New(WithRegistrationHook(func(_ reflect.Type, instance any) (any, error) {
+			return &TestRepository{}, nil
+		}))
+
+		if err := c.Register(&TestService{}); err == nil || !strings.Contains(err.Error(), "*goinject.TestRepository") {
+			t.Errorf("Register() error = %v, want the hook result rejected", err)
+		}
+
+		if err := c.RegisterFactory(func() *TestService { return &TestService{} }); err != nil {
+			t.Fatalf("failed to register factory: %v", err)
+		}
+
+		if _, err := Get[TestService](c); err == nil || errors.Is(err, ErrOutputMustBeAPointer) {
+			t.Errorf("Get[T]() error = %v, want the hook result rejected", err)
+		}
+	})
+}
+
+func TestWithRegistrationHook_EntryPoints(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	reject := WithRegistrationHook(func(reflect.Type, any) (any, error) {
+		return nil, errRejected
+	})
+
+	instances := map[string]func(c *Container) error{
+		"RegisterAs":           func(c *Container) error { return RegisterAs[TestReader](c, &TestStore{}) },
+		"RegisterWithPriority": func(c *Container) error { return c.RegisterWithPriority(&TestService{}, 1) },
+		"RegisterTagged":       func(c *Container) error { return c.RegisterTagged(&TestService{}, "tag") },
+		"RegisterNamed":        func(c *Container) error { return c.RegisterNamed("name", &TestService{}) },
+		"RegisterProfile":      func(c *Container) error { return c.RegisterProfile("prod", &TestService{}) },
+		"RegisterWithFinalizer": func(c *Container) error {
+			return c.RegisterWithFinalizer(&TestService{}, func(any) error { return nil })
+		},
+		"RegisterOrdered": func(c *Container) error { return c.RegisterOrdered(&TestService{}, 1) },
+		"RegisterOrReplace": func(c *Container) error {
+			_, err := c.RegisterOrReplace(&TestService{})
+			return err
+		},
+		"Replace": func(c *Container) error {
+			_, err := Replace(c, &TestService{})
+			return err
+		},
+	}
+
+	for name, register := range instances {
+		t.Run(name, func(t *testing.T) {
+			if err := register(New(reject, WithActiveProfiles("prod"))); !errors.Is(err, errRejected) {
+				t.Errorf("%s() error = %v, want %v", name, err, errRejected)
+			}
+		})
+	}
+
+	factories := map[string]func(c *Container) error{
+		"Provide": func(c *Container) error { return c.Provide(func() *TestStore { return &TestStore{} }) },
+		"Bind":    func(c *Container) error { return Bind[TestReader](c, func() *TestStore { return &TestStore{} }) },
+		"BindMulti": func(c *Container) error {
+			return BindMulti(c, func() *TestStore { return &TestStore{} }, (*TestReader)(nil))
+		},
+		"RegisterFunc": func(c *Container) error {
+			return RegisterFunc(c, func() TestStore { return TestStore{} })
+		},
+		"RegisterOrderedFactory": func(c *Container) error {
+			return c.RegisterOrdered(func() *TestStore { return &TestStore{} }, 1)
+		},
+	}
+
+	for name, register := range factories {
+		t.Run(name, func(t *testing.T) {
+			c := New(reject)
+
+			if err := register(c); err != nil {
+				t.Fatalf("%s() unexpected error = %v", name, err)
+			}
+
+			if _, err := Get[TestStore](c); !errors.Is(err, errRejected) {
+				t.Errorf("Get[T]() after %s() error = %v, want %v", name, err, errRejected)
+			}
+		})
+	}
+
+	t.Run("RegisterFactoryConstrained", func(t *testing.T) {
+		c := New(reject)
+
+		if err := RegisterFactoryConstrained(c, func() *int { return new(int) }); err != nil {
+			t.Fatalf("RegisterFactoryConstrained() unexpected error = %v", err)
+		}
+
+		if _, err := Get[int](c); !errors.Is(err, errRejected) {
+			t.Errorf("Get[T]() error = %v, want %v", err, errRejected)
+		}
+	})
+
+	t.Run("ProvideNamed", func(t *testing.T) {
+		c := New(reject)
+
+		if err := c.ProvideNamed("name", func() *TestStore { return &TestStore{} }); err != nil {
+			t.Fatalf("ProvideNamed() unexpected error = %v", err)
+		}
+
+		if _, err := c.GetNamed("name", &TestStore{}); !errors.Is(err, errRejected) {
+			t.Errorf("GetNamed() error = %v, want %v", err, errRejected)
+		}
+	})
+}
+
+func TestWithFallbackProvider(t *testing.T) {
+	var lookups int
+
+	c := New(WithFallbackProvider(func(typeof reflect.Type) (any, bool) {
+		lookups++
+
+		if typeof == reflect.TypeFor[*TestService]() {
+			return &TestService{Name: "external"}, true
+		}
+
+		return nil, false
+	}))
+
+	for range 2 {
+		result, err := Get[TestService](c)
+		if err != nil || result.Name != "external" {
+			t.Fatalf("Get[T]() got = %v, %v, want the external service", result, err)
+		}
+	}
+
+	if lookups != 2 {
+		t.Errorf("fallback consulted %d times, want 2", lookups)
+	}
+
+	if types := c.RegisteredTypes(); len(types) != 0 {
+		t.Errorf("RegisteredTypes() got = %v, the fallback result should not be retained", types)
+	}
+
+	if _, err := Get[AnotherService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestWithStrictInterfaces(t *testing.T) {
+	c := New(WithStrictInterfaces(), WithStructuralResolution())
+
+	if err := c.Register(&bytes.Buffer{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	_, err := Get[io.Writer](c)
+	if !errors.Is(err, ErrNoBinding) || !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[io.Writer]() error = %v, want %v", err, ErrNoBinding)
+	}
+
+	if _, err := c.Plan((*io.Writer)(nil)); !errors.Is(err, ErrNoBinding) {
+		t.Errorf("Plan() error = %v, want %v", err, ErrNoBinding)
+	}
+
+	// Collections still gather every implementation.
+	if writers, err := GetSlice[io.Writer](c); err != nil || len(writers) != 1 {
+		t.Errorf("GetSlice[io.Writer]() got = %v, %v, want the buffer", writers, err)
+	}
+
+	store := &TestStore{}
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("failed to bind TestReader: %v", err)
+	}
+
+	if reader, err := Get[TestReader](c); err != nil || *reader != TestReader(store) {
+		t.Errorf("Get[TestReader]() got = %v, %v, want %v", reader, err, store)
+	}
+}
+
+func TestWithStrictInterfaces_Absent(t *testing.T) {
+	c := New(WithStrictInterfaces())
+
+	if writer, ok, err := GetOk[io.Writer](c); writer != nil || ok || err != nil {
+		t.Errorf("GetOk[io.Writer]() got = %v, %v, %v, want absent", writer, ok, err)
+	}
+
+	if writer := GetOrNil[io.Writer](c); writer != nil {
+		t.Errorf("GetOrNil[io.Writer]() got = %v, want nil", writer)
+	}
+
+	var writer io.Writer
+	fallback := &bytes.Buffer{}
+
+	if got, err := c.GetOrElse(&writer, func(reflect.Type) (any, error) { return fallback, nil }); err != nil || got != fallback {
+		t.Errorf("GetOrElse() got = %v, %v, want the fallback", got, err)
+	}
+
+	// A miss further down the graph is a failure, not an absence.
+	if err := c.Provide(func(w io.Writer) *TestService { return &TestService{} }); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if _, ok, err := GetOk[TestService](c); !ok || !errors.Is(err, ErrNoBinding) {
+		t.Errorf("GetOk[TestService]() got = %v, %v, want registered but failing with %v", ok, err, ErrNoBinding)
+	}
+}
+
+func TestWithReflectiveDefault(t *testing.T) {
+	c := New(WithReflectiveDefault())
+
+	if err := c.Provide(func(s *TestService) *TestRepository { return &TestRepository{Service: s} }); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if missing := c.MissingDependencies(); len(missing) != 0 {
+		t.Errorf("MissingDependencies() got = %v, want none", missing)
+	}
+
+	repository, err := Get[TestRepository](c)
+	if err != nil || repository.Service == nil || *repository.Service != (TestService{}) {
+		t.Fatalf("Get[T]() got = %v, %v, want a repository of a zero service", repository, err)
+	}
+
+	if service := MustGet[TestService](c); service != repository.Service {
+		t.Errorf("Get[T]() got = %p, want the cached zero value %p", service, repository.Service)
+	}
+
+	if _, err := Get[TestReader](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[TestReader]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if _, err := Get[int](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[int]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if _, err := Get[TestService](New()); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() without WithReflectiveDefault error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestWithResolveMetric(t *testing.T) {
+	type metric struct {
+		typeof reflect.Type
+		d      time.Duration
+		cached bool
+		err    error
+	}
+
+	var metrics []metric
+
+	c := New(WithResolveMetric(func(t reflect.Type, d time.Duration, cached bool, err error) {
+		metrics = append(metrics, metric{t, d, cached, err})
+	}))
+
+	if err := c.RegisterFactory(func() *TestService {
+		time.Sleep(time.Millisecond)
+		return &TestService{}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	for range 2 {
+		if _, err := Get[TestService](c); err != nil {
+			t.Fatalf("Get[T]() unexpected error = %v", err)
+		}
+	}
+
+	if _, err := Get[AnotherService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	if len(metrics) != 3 {
+		t.Fatalf("callback invoked %d times, want 3", len(metrics))
+	}
+
+	if built := metrics[0]; built.typeof != reflect.TypeFor[*TestService]() || built.cached || built.d < time.Millisecond || built.err != nil {
+		t.Errorf("first resolution got = %+v, want a build of at least 1ms", built)
+	}
+
+	if cached := metrics[1]; !cached.cached || cached.err != nil {
+		t.Errorf("second resolution got = %+v, want a cached resolution", cached)
+	}
+
+	if failed := metrics[2]; failed.cached || !errors.Is(failed.err, ErrServiceNotFound) {
+		t.Errorf("failed resolution got = %+v, want %v", failed, ErrServiceNotFound)
+	}
+}
@@ -0,0 +1,51 @@
+package goinject
+
+import (
+	"context"
+	"reflect"
+)
+
+// Placeholder is a handle to a service of type *T that is registered now
+// and filled later with SetPlaceholder.
+type Placeholder[T any] struct {
+	c *Container
+}
+
+// RegisterPlaceholder registers a placeholder for *T and returns its handle.
+// Resolving *T before the placeholder is set returns ErrPlaceholderUnset.
+// It panics with a *MustPanic if the placeholder cannot be registered,
+// for example because the container is frozen or closed.
+//
+// Example:
+//
+//	handle := goinject.RegisterPlaceholder[Config](container)
+//	// ... later, once the config is loaded
+//	goinject.SetPlaceholder(handle, &Config{Env: "prod"})
+func RegisterPlaceholder[T any](c *Container) *Placeholder[T] {
+
+	typeof := reflect.TypeOf((*T)(nil))
+
+	err := c.addFactory(typeof, newFactory(typeof, func(context.Context, *Container) (any, error) {
+		return nil, ErrPlaceholderUnset
+	}))
+	{
+		if err != nil {
+			panic(c.mustPanic("RegisterPlaceholder", typeof, err))
+		}
+	}
+
+	return &Placeholder[T]{c: c}
+}
+
+// SetPlaceholder fills the placeholder with the given instance.
+// Subsequent resolutions of *T return the instance.
+//
+// Example:
+//
+//	err := goinject.SetPlaceholder(handle, &Config{Env: "prod"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func SetPlaceholder[T any](p *Placeholder[T], instance *T) error {
+	return p.c.Register(instance)
+}
@@ -0,0 +1,62 @@
+package goinject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlaceholder_ResolveAfterSet(t *testing.T) {
+	c := New()
+	handle := RegisterPlaceholder[TestService](c)
+
+	service := &TestService{Name: "late"}
+	if err := SetPlaceholder(handle, service); err != nil {
+		t.Fatalf("SetPlaceholder() unexpected error = %v", err)
+	}
+
+	result, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if result != service {
+		t.Errorf("Get[T]() got = %p, want %p", result, service)
+	}
+}
+
+func TestPlaceholder_ResolveBeforeSet(t *testing.T) {
+	c := New()
+	handle := RegisterPlaceholder[TestService](c)
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrPlaceholderUnset) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrPlaceholderUnset)
+	}
+
+	// The failed resolution must not prevent the placeholder from being set.
+	if err := SetPlaceholder(handle, &TestService{Name: "late"}); err != nil {
+		t.Fatalf("SetPlaceholder() unexpected error = %v", err)
+	}
+
+	result, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if result.Name != "late" {
+		t.Errorf("Get[T]() got = %v, want %v", result.Name, "late")
+	}
+}
+
+func TestPlaceholder_RegisterFrozen(t *testing.T) {
+	c := New()
+	c.Freeze()
+
+	defer func() {
+		if p, ok := recover().(*MustPanic); !ok || p.Op != "RegisterPlaceholder" || !errors.Is(p, ErrContainerFrozen) {
+			t.Errorf("RegisterPlaceholder() panicked with %v, want a *MustPanic wrapping %v", p, ErrContainerFrozen)
+		}
+	}()
+
+	RegisterPlaceholder[TestService](c)
+	t.Error("RegisterPlaceholder() on a frozen container should panic")
+}
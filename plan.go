@@ -0,0 +1,311 @@
+package goinject
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Plan reports, without invoking any factory, the types that resolving out would construct,
+// dependencies first. Registered instances and already built singletons are not part of the plan.
+// Dependencies a scope does not register are planned from its parents, as they are resolved.
+// It returns an error if a dependency on the way is not registered.
+//
+// Example:
+//
+//	var handler UserHandler
+//	plan, err := container.Plan(&handler)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(plan) // [*main.DB *main.UserRepository *main.UserHandler]
+func (c *Container) Plan(out any) ([]reflect.Type, error) {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.plan(typeof)
+}
+
+// plan returns the types that resolving typeof would construct, dependencies first.
+// It must be called with the lock held.
+func (c *Container) plan(typeof typeof) ([]reflect.Type, error) {
+
+	p := &planner{c: c, planned: make(map[reflect.Type]bool)}
+
+	if err := p.visit(typeof); err != nil {
+		return nil, err
+	}
+
+	return p.plan, nil
+}
+
+// ResolutionDepth reports, without invoking any factory, how many construction steps resolving
+// out would take: the number of types in its plan, each shared dependency counted once.
+// It returns an error if a dependency on the way is not registered.
+//
+// Example:
+//
+//	if depth, err := container.ResolutionDepth(&handler); err != nil || depth > 10 {
+//	    t.Errorf("UserHandler takes %d construction steps: %v", depth, err)
+//	}
+func (c *Container) ResolutionDepth(out any) (int, error) {
+
+	plan, err := c.Plan(out)
+	{
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(plan), nil
+}
+
+// BuildOrder reports, without invoking any factory, the order in which Build would construct
+// the singletons, dependencies first: roots are taken by the hints given to RegisterOrdered and
+// by registration order otherwise, so the order is stable for golden tests. Transient, scoped
+// and goroutine-local types and singletons already built are not part of it. It returns an
+// error matching ErrCircularDependency on a cycle, or ErrServiceNotFound if a dependency
+// is not registered.
+//
+// Example:
+//
+//	order, err := container.BuildOrder()
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	golden.Assert(t, fmt.Sprint(order), "wiring.golden")
+func (c *Container) BuildOrder() ([]reflect.Type, error) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var roots []typeof
+	for _, typeof := range c.order {
+		if factory := c.factories[typeof]; factory != nil && factory.eager() {
+			roots = append(roots, typeof)
+		}
+	}
+
+	slices.SortStableFunc(roots, func(a, b reflect.Type) int {
+		return cmp.Compare(c.hints[a], c.hints[b])
+	})
+
+	p := &planner{c: c, planned: make(map[reflect.Type]bool)}
+
+	for _, typeof := range roots {
+		if err := p.visit(typeof); err != nil {
+			return nil, err
+		}
+	}
+
+	return slices.DeleteFunc(p.plan, func(t reflect.Type) bool {
+		_, factory, _, _ := p.find(t)
+		return !factory.eager()
+	}), nil
+}
+
+// MissingDependencies reports, for every registered constructor, the declared dependencies
+// that nothing is registered for. Constructors whose dependencies are all registered are left out.
+// Nothing is constructed; unlike Plan, the dependencies of dependencies are not followed.
+//
+// Example:
+//
+//	for typeof, missing := range container.MissingDependencies() {
+//	    log.Printf("%s needs %v", typeof, missing)
+//	}
+func (c *Container) MissingDependencies() map[reflect.Type][]reflect.Type {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	report := make(map[reflect.Type][]reflect.Type)
+
+	for _, typeof := range c.order {
+		factory := c.factories[typeof]
+		if factory == nil {
+			continue
+		}
+
+		for i, dep := range factory.deps {
+			// Supplied by the resolution, or an empty slice at worst.
+			if dep == contextType || dep == containerType || dep == typeType || factory.variadic && i == len(factory.deps)-1 {
+				continue
+			}
+
+			if c.registered(dep) || c.inherited(dep) || c.defaultable(dep) {
+				continue
+			}
+
+			if c.structural && dep.Kind() == reflect.Interface && len(c.matching(dep)) > 0 {
+				continue
+			}
+
+			report[typeof] = append(report[typeof], dep)
+		}
+	}
+
+	return report
+}
+
+// planner walks the dependency graph of the registrations.
+// It must only be used with the container lock held.
+type planner struct {
+	c        *Container
+	planned  map[typeof]bool
+	visiting []typeof
+	plan     []reflect.Type
+}
+
+// visit appends the types needed to construct typeof to the plan.
+func (p *planner) visit(typeof typeof) error {
+
+	if p.planned[typeof] {
+		return nil
+	}
+
+	if slices.Contains(p.visiting, typeof) {
+		return fmt.Errorf("%w: %s", ErrCircularDependency, p.c.names(append(p.visiting, typeof)))
+	}
+
+	provided, factory, target, owner := p.find(typeof)
+
+	if provided {
+		return nil
+	}
+
+	if factory == nil {
+		if target != nil {
+			return p.visit(target)
+		}
+
+		next, err := p.missing(owner, typeof)
+		{
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		return p.visit(next)
+	}
+
+	if _, ok := p.cached(typeof, factory); ok {
+		return nil
+	}
+
+	p.visiting = append(p.visiting, typeof)
+
+	for i, dep := range factory.deps {
+		// The context, the container and the requester are supplied by the resolution itself.
+		if dep == contextType || dep == containerType || dep == typeType {
+			continue
+		}
+
+		if factory.variadic && i == len(factory.deps)-1 {
+			for _, member := range p.c.matching(dep.Elem()) {
+				if err := p.visit(member); err != nil {
+					return fmt.Errorf("dependency %s of %s: %w", p.c.name(dep), p.c.name(typeof), err)
+				}
+			}
+
+			continue
+		}
+
+		if err := p.visit(dep); err != nil {
+			return fmt.Errorf("dependency %s of %s: %w", p.c.name(dep), p.c.name(typeof), err)
+		}
+	}
+
+	p.visiting = p.visiting[:len(p.visiting)-1]
+
+	p.planned[typeof] = true
+	p.plan = append(p.plan, typeof)
+
+	return nil
+}
+
+// find returns the registration of typeof in the container or, like lookupFor, in its
+// parents, and the container it was found in: the outermost one if there is none.
+// Every container but the planned one is read under its own lock.
+func (p *planner) find(typeof typeof) (provided bool, factory *factory, target typeof, owner *Container) {
+
+	for owner = p.c; ; owner = owner.parent {
+		if owner != p.c {
+			owner.mu.RLock()
+		}
+
+		_, provided = owner.providers[typeof]
+		factory = owner.factories[typeof]
+		target = owner.bindings[typeof]
+
+		if owner != p.c {
+			owner.mu.RUnlock()
+		}
+
+		if provided || factory != nil || target != nil || owner.parent == nil {
+			return provided, factory, target, owner
+		}
+	}
+}
+
+// cached returns the instance the factory already built for the planned container.
+// Scoped factories cache it in the scope resolving them.
+func (p *planner) cached(typeof typeof, factory *factory) (any, bool) {
+
+	if factory.lifetime == Scoped {
+		if local := p.c.scoped[typeof]; local != nil {
+			return local.cached()
+		}
+
+		return nil, false
+	}
+
+	return factory.cached()
+}
+
+// missing handles a type nothing is registered for the way the resolveMissing of owner
+// would: it returns the single registration a structural match resolves it to, nil
+// if the type is resolved without construction, or the error the resolution would fail with.
+func (p *planner) missing(owner *Container, typeof typeof) (typeof, error) {
+
+	if owner != p.c {
+		owner.mu.RLock()
+		defer owner.mu.RUnlock()
+	}
+
+	if owner.strictInterfaces && typeof.Kind() == reflect.Interface {
+		return nil, owner.unbound(typeof)
+	}
+
+	if owner.structural && typeof.Kind() == reflect.Interface {
+		if candidates := owner.matching(typeof); len(candidates) == 1 {
+			return candidates[0], nil
+		} else if len(candidates) > 1 {
+			return nil, fmt.Errorf("%w: %s is implemented by %s", ErrAmbiguousResolution, owner.name(typeof), owner.names(candidates))
+		}
+	}
+
+	if owner.defaultable(typeof) {
+		return nil, nil
+	}
+
+	if typeof.Kind() == reflect.Interface {
+		if err := owner.composite(typeof); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, ErrServiceNotFound
+}
@@ -0,0 +1,285 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestContainer_Plan(t *testing.T) {
+	c := New()
+	built := 0
+
+	if err := c.ProvideAll(
+		func(r *TestRepository) *TestHandler {
+			built++
+			return &TestHandler{Repository: r}
+		},
+		func(s *TestService) *TestRepository {
+			built++
+			return &TestRepository{Service: s}
+		},
+		func() *TestService {
+			built++
+			return &TestService{Name: "test"}
+		},
+	); err != nil {
+		t.Fatalf("ProvideAll() unexpected error = %v", err)
+	}
+
+	plan, err := c.Plan(&TestHandler{})
+	if err != nil {
+		t.Fatalf("Plan() unexpected error = %v", err)
+	}
+
+	want := []reflect.Type{
+		reflect.TypeOf(&TestService{}),
+		reflect.TypeOf(&TestRepository{}),
+		reflect.TypeOf(&TestHandler{}),
+	}
+
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("Plan() got = %v, want %v", plan, want)
+	}
+
+	if built != 0 {
+		t.Errorf("Plan() constructed %d services, want none", built)
+	}
+
+	// Built singletons are no longer part of the plan.
+	if _, err := Get[TestService](c); err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	plan, err = c.Plan(&TestHandler{})
+	if err != nil {
+		t.Fatalf("Plan() unexpected error = %v", err)
+	}
+
+	if !reflect.DeepEqual(plan, want[1:]) {
+		t.Errorf("Plan() after Get got = %v, want %v", plan, want[1:])
+	}
+}
+
+func TestContainer_PlanMissingDependency(t *testing.T) {
+	c := New()
+
+	if err := c.ProvideAll(
+		func(r *TestRepository) *TestHandler { return &TestHandler{Repository: r} },
+		func(s *TestService) *TestRepository { return &TestRepository{Service: s} },
+	); err != nil {
+		t.Fatalf("ProvideAll() unexpected error = %v", err)
+	}
+
+	if _, err := c.Plan(&TestHandler{}); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Plan() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestContainer_PlanScope(t *testing.T) {
+	c := New()
+
+	if err := c.ProvideAll(
+		func(s *TestService) *TestRepository { return &TestRepository{Service: s} },
+		func() *TestService { return &TestService{Name: "test"} },
+	); err != nil {
+		t.Fatalf("ProvideAll() unexpected error = %v", err)
+	}
+
+	scope := c.Scope()
+
+	if err := scope.Provide(func(r *TestRepository) *TestHandler { return &TestHandler{Repository: r} }); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	want := []reflect.Type{
+		reflect.TypeOf(&TestService{}),
+		reflect.TypeOf(&TestRepository{}),
+		reflect.TypeOf(&TestHandler{}),
+	}
+
+	if plan, err := scope.Plan(&TestHandler{}); err != nil || !reflect.DeepEqual(plan, want) {
+		t.Errorf("Plan() got = %v, %v, want %v", plan, err, want)
+	}
+
+	if depth, err := scope.ResolutionDepth(&TestHandler{}); err != nil || depth != len(want) {
+		t.Errorf("ResolutionDepth() got = %d, %v, want %d", depth, err, len(want))
+	}
+
+	if order, err := scope.BuildOrder(); err != nil || !reflect.DeepEqual(order, want) {
+		t.Errorf("BuildOrder() got = %v, %v, want %v", order, err, want)
+	}
+
+	if missing := scope.MissingDependencies(); len(missing) != 0 {
+		t.Errorf("MissingDependencies() got = %v, want none", missing)
+	}
+}
+
+func TestContainer_MissingDependencies(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func(*TestService, *AnotherService) *TestRepository {
+		return &TestRepository{}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if err := c.Provide(func(TestReader, TestWriter, ...TestPlugin) *TestHandler {
+		return &TestHandler{}
+	}); err != nil {
+		t.Fatalf("failed to provide handler: %v", err)
+	}
+
+	if err := c.Provide(func(*TestService) *TestStore {
+		return &TestStore{}
+	}); err != nil {
+		t.Fatalf("failed to provide store: %v", err)
+	}
+
+	want := map[reflect.Type][]reflect.Type{
+		reflect.TypeOf(&TestRepository{}): {reflect.TypeOf(&AnotherService{})},
+		reflect.TypeOf(&TestHandler{}):    {reflect.TypeFor[TestReader](), reflect.TypeFor[TestWriter]()},
+	}
+
+	if got := c.MissingDependencies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingDependencies() got = %v, want %v", got, want)
+	}
+}
+
+func TestContainer_ResolutionDepth(t *testing.T) {
+	type (
+		Base  struct{}
+		Left  struct{ *Base }
+		Right struct{ *Base }
+		Top   struct {
+			*Left
+			*Right
+		}
+	)
+
+	tests := []struct {
+		name    string
+		ctors   []any
+		out     any
+		want    int
+		wantErr error
+	}{
+		{
+			name: "linear",
+			ctors: []any{
+				func(r *TestRepository) *TestHandler { return &TestHandler{Repository: r} },
+				func(s *TestService) *TestRepository { return &TestRepository{Service: s} },
+				func() *TestService { return &TestService{} },
+			},
+			out:  &TestHandler{},
+			want: 3,
+		},
+		{
+			name: "diamond",
+			ctors: []any{
+				func(l *Left, r *Right) *Top { return &Top{l, r} },
+				func(b *Base) *Left { return &Left{b} },
+				func(b *Base) *Right { return &Right{b} },
+				func() *Base { return &Base{} },
+			},
+			out:  &Top{},
+			want: 4,
+		},
+		{
+			name: "unsatisfiable",
+			ctors: []any{
+				func(r *TestRepository) *TestHandler { return &TestHandler{Repository: r} },
+			},
+			out:     &TestHandler{},
+			wantErr: ErrServiceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+
+			if err := c.ProvideAll(tt.ctors...); err != nil {
+				t.Fatalf("ProvideAll() unexpected error = %v", err)
+			}
+
+			depth, err := c.ResolutionDepth(tt.out)
+			if !errors.Is(err, tt.wantErr) || depth != tt.want {
+				t.Errorf("ResolutionDepth() got = %d, %v, want %d, %v", depth, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainer_BuildOrder(t *testing.T) {
+	type (
+		Clock   struct{}
+		Request struct{}
+		Store   struct{ *Clock }
+		Cache   struct {
+			*Clock
+			*Request
+		}
+		Server struct {
+			*Store
+			*Cache
+		}
+	)
+
+	c := New()
+
+	if err := c.ProvideAll(
+		func(s *Store, ca *Cache) *Server { return &Server{s, ca} },
+		func(cl *Clock, r *Request) *Cache { return &Cache{cl, r} },
+		func(cl *Clock) *Store { return &Store{cl} },
+		func() *Clock { return &Clock{} },
+	); err != nil {
+		t.Fatalf("ProvideAll() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterTransient(func() *Request { return &Request{} }); err != nil {
+		t.Fatalf("RegisterTransient() unexpected error = %v", err)
+	}
+
+	want := []reflect.Type{
+		reflect.TypeFor[*Clock](),
+		reflect.TypeFor[*Store](),
+		reflect.TypeFor[*Cache](),
+		reflect.TypeFor[*Server](),
+	}
+
+	for range 3 {
+		order, err := c.BuildOrder()
+		if err != nil {
+			t.Fatalf("BuildOrder() unexpected error = %v", err)
+		}
+
+		if !reflect.DeepEqual(order, want) {
+			t.Fatalf("BuildOrder() got = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestContainer_BuildOrderCycle(t *testing.T) {
+	type (
+		Egg     struct{}
+		Chicken struct{}
+	)
+
+	c := New()
+
+	if err := c.ProvideAll(
+		func(*Egg) *Chicken { return &Chicken{} },
+		func(*Chicken) *Egg { return &Egg{} },
+	); err != nil {
+		t.Fatalf("ProvideAll() unexpected error = %v", err)
+	}
+
+	if _, err := c.BuildOrder(); !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("BuildOrder() error = %v, want %v", err, ErrCircularDependency)
+	}
+}
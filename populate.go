@@ -0,0 +1,104 @@
+package goinject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// DependencyDeclarer is implemented by services that declare what they need themselves,
+// for types that cannot carry struct tags. Populate fills the exported fields of the declared
+// types without an `inject` tag, and Build checks that the dependencies declared by registered
+// instances can be resolved.
+type DependencyDeclarer interface {
+	Dependencies() []reflect.Type
+}
+
+// Populate fills the fields of the struct target points to that carry an `inject` tag.
+// Each tagged field is resolved by its type, like Get: *T fields receive the service
+// registered for *T and interface fields the service registered for the interface.
+// If target implements DependencyDeclarer, every declared dependency is resolved as well
+// and assigned to the exported fields of its type.
+// It returns an error naming the first field or dependency that could not be filled.
+//
+// Example:
+//
+//	type Server struct {
+//	    DB     *DB    `inject:""`
+//	    Logger Logger `inject:""`
+//	}
+//
+//	var server Server
+//	err := container.Populate(&server)
+func (c *Container) Populate(target any) error {
+
+	value := reflect.ValueOf(target)
+	{
+		if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("%w to a struct, got %s", ErrOutputMustBeAPointer, c.nameOf(target))
+		}
+	}
+
+	var deps []reflect.Type
+	if declarer, ok := target.(DependencyDeclarer); ok {
+		deps = declarer.Dependencies()
+	}
+
+	declared := make(map[reflect.Type]bool, len(deps))
+	for _, dep := range deps {
+		declared[dep] = true
+	}
+
+	value = value.Elem()
+
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+
+		if _, ok := field.Tag.Lookup("inject"); !ok {
+			if !declared[field.Type] || !field.IsExported() {
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			return fmt.Errorf("field %s of %s is unexported", field.Name, c.name(value.Type()))
+		}
+
+		service, err := c.resolve(context.Background(), field.Type)
+		{
+			if err != nil {
+				return fmt.Errorf("field %s of %s: %w", field.Name, c.name(value.Type()), err)
+			}
+		}
+
+		value.Field(i).Set(reflect.ValueOf(service))
+		delete(declared, field.Type)
+	}
+
+	// Declared dependencies without a field are still resolved, so that they are checked.
+	for _, dep := range deps {
+		if !declared[dep] {
+			continue
+		}
+
+		if _, err := c.resolve(context.Background(), dep); err != nil {
+			return fmt.Errorf("dependency %s of %s: %w", c.name(dep), c.name(value.Type()), err)
+		}
+	}
+
+	return nil
+}
+
+// MustPopulate fills the tagged fields of target like Populate.
+// It panics with a *MustPanic wrapping the error that names the failing field
+// if any field cannot be filled.
+//
+// Example:
+//
+//	var server Server
+//	container.MustPopulate(&server)
+func (c *Container) MustPopulate(target any) {
+	if err := c.Populate(target); err != nil {
+		panic(c.mustPanic("MustPopulate", reflect.TypeOf(target), err))
+	}
+}
@@ -0,0 +1,152 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type TestPopulated struct {
+	Service    *TestService    `inject:""`
+	Repository *TestRepository `inject:""`
+	Reader     TestReader      `inject:""`
+	Untagged   *AnotherService
+}
+
+// TestDeclarer declares its dependencies instead of tagging its fields.
+type TestDeclarer struct {
+	Service *TestService
+	Reader  TestReader
+}
+
+func (*TestDeclarer) Dependencies() []reflect.Type {
+	return []reflect.Type{reflect.TypeFor[*TestService](), reflect.TypeFor[TestReader]()}
+}
+
+func TestContainer_Populate(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+	store := &TestStore{}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("failed to register reader: %v", err)
+	}
+
+	var target TestPopulated
+	if err := c.Populate(&target); err != nil {
+		t.Fatalf("Populate() unexpected error = %v", err)
+	}
+
+	if target.Service != service || target.Repository.Service != service || target.Reader != TestReader(store) {
+		t.Errorf("Populate() got = %+v", target)
+	}
+
+	if target.Untagged != nil {
+		t.Errorf("Populate() filled untagged field with %v", target.Untagged)
+	}
+
+	if err := c.Populate(target); !errors.Is(err, ErrOutputMustBeAPointer) {
+		t.Errorf("Populate() error = %v, want %v", err, ErrOutputMustBeAPointer)
+	}
+}
+
+func TestContainer_MustPopulate(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	var satisfiable struct {
+		Service *TestService `inject:""`
+	}
+
+	c.MustPopulate(&satisfiable)
+
+	if satisfiable.Service == nil || satisfiable.Service.Name != "test" {
+		t.Errorf("MustPopulate() got = %v", satisfiable.Service)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+
+		if !errors.Is(err, ErrServiceNotFound) || !strings.Contains(err.Error(), "field Repository") {
+			t.Errorf("MustPopulate() panic = %v, want the failing field and %v", err, ErrServiceNotFound)
+		}
+	}()
+
+	var missing TestPopulated
+	c.MustPopulate(&missing)
+
+	t.Error("MustPopulate() expected a panic")
+}
+
+func TestContainer_PopulateDependencyDeclarer(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+	store := &TestStore{}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	var target TestDeclarer
+	if err := c.Populate(&target); !errors.Is(err, ErrServiceNotFound) || !strings.Contains(err.Error(), "field Reader") {
+		t.Errorf("Populate() error = %v, want %v naming field Reader", err, ErrServiceNotFound)
+	}
+
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("failed to register reader: %v", err)
+	}
+
+	if err := c.Populate(&target); err != nil {
+		t.Fatalf("Populate() unexpected error = %v", err)
+	}
+
+	if target.Service != service || target.Reader != TestReader(store) {
+		t.Errorf("Populate() got = %+v", target)
+	}
+}
+
+func TestContainer_BuildDependencyDeclarer(t *testing.T) {
+	c := New()
+	built := false
+
+	if err := c.Register(&TestDeclarer{}); err != nil {
+		t.Fatalf("failed to register declarer: %v", err)
+	}
+
+	if err := c.RegisterFactory(func() *TestService {
+		built = true
+		return &TestService{}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.Build(); !errors.Is(err, ErrServiceNotFound) || !strings.Contains(err.Error(), "TestReader") {
+		t.Errorf("Build() error = %v, want %v naming TestReader", err, ErrServiceNotFound)
+	}
+
+	if built {
+		t.Error("Build() should check declared dependencies before building anything")
+	}
+
+	if err := RegisterAs[TestReader](c, &TestStore{}); err != nil {
+		t.Fatalf("failed to register reader: %v", err)
+	}
+
+	if err := c.Build(); err != nil || !built {
+		t.Errorf("Build() got error = %v, built = %v, want nil, true", err, built)
+	}
+}
@@ -0,0 +1,27 @@
+package goinject
+
+// RegisterProfile registers a singleton instance that only takes part in the given profile.
+// When the profile is not activated with WithActiveProfiles the registration is skipped,
+// so the type stays unresolvable unless it is registered some other way.
+// Registrations made with Register belong to no profile and are always active.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithActiveProfiles("prod"))
+//	container.RegisterProfile("prod", &SMTPMailer{})
+//	container.RegisterProfile("dev", &ConsoleMailer{}) // skipped
+func (c *Container) RegisterProfile(profile string, service any) error {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	if !c.profiles[profile] {
+		return nil
+	}
+
+	return c.addProvider(typeof, service)
+}
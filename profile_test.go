@@ -0,0 +1,73 @@
+package goinject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestContainer_RegisterProfile(t *testing.T) {
+	register := func(c *Container) {
+		if err := c.RegisterProfile("prod", &TestService{Name: "prod"}); err != nil {
+			t.Fatalf("RegisterProfile() unexpected error = %v", err)
+		}
+
+		if err := c.RegisterProfile("dev", &TestService{Name: "dev"}); err != nil {
+			t.Fatalf("RegisterProfile() unexpected error = %v", err)
+		}
+
+		if err := c.RegisterProfile("dev", &AnotherService{ID: 1}); err != nil {
+			t.Fatalf("RegisterProfile() unexpected error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		profiles []string
+		want     string
+		wantDev  bool
+	}{
+		{name: "prod", profiles: []string{"prod", "eu"}, want: "prod"},
+		{name: "dev", profiles: []string{"dev"}, want: "dev", wantDev: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(WithActiveProfiles(tt.profiles...))
+			register(c)
+
+			result, err := Get[TestService](c)
+			if err != nil {
+				t.Fatalf("Get[T]() unexpected error = %v", err)
+			}
+
+			if result.Name != tt.want {
+				t.Errorf("Get[T]() got = %v, want %v", result.Name, tt.want)
+			}
+
+			if _, err := Get[AnotherService](c); (err == nil) != tt.wantDev {
+				t.Errorf("Get[AnotherService]() error = %v, want found %v", err, tt.wantDev)
+			}
+		})
+	}
+}
+
+func TestContainer_RegisterProfileInactive(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterProfile("prod", &TestService{Name: "prod"}); err != nil {
+		t.Fatalf("RegisterProfile() unexpected error = %v", err)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	// Registrations without a profile are always active.
+	if err := c.Register(&TestService{Name: "default"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Errorf("Get[T]() unexpected error = %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package goinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+var (
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	containerType = reflect.TypeOf((*Container)(nil))
+	typeType      = reflect.TypeFor[reflect.Type]()
+)
+
+// requested is the context of the resolution of a dependency, which records the type
+// whose constructor requests it. Constructors receive the context it wraps.
+type requested struct {
+	context.Context
+	by reflect.Type
+}
+
+// Provide registers a constructor whose arguments are resolved from the container.
+// The constructor must return a pointer, optionally followed by an error.
+// Like factories, the constructed instance is created once and reused.
+// A variadic parameter receives every registration assignable to its element type,
+// in registration order, or nothing if there is none. A context.Context parameter
+// receives the context of the resolution, see GetContext, and a *Container parameter
+// receives the container itself. A reflect.Type parameter receives the type whose constructor
+// requested the instance, or the requested type itself for a top-level resolution; since the
+// instance then depends on its consumer, it is built anew on every resolution, like a transient.
+//
+// Example:
+//
+//	container.Register(&DB{DSN: "postgres://localhost"})
+//	container.Provide(func(db *DB) (*UserRepository, error) {
+//	    return NewUserRepository(db)
+//	})
+//
+//	// a logger tagged with the type of its consumer
+//	container.Provide(func(requestedBy reflect.Type) *Logger {
+//	    return NewLogger(requestedBy.String())
+//	})
+func (c *Container) Provide(ctor any) error {
+
+	typeof, factory, err := c.parseConstructor(ctor)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.addFactory(typeof, factory)
+}
+
+// ProvideResult registers a fallible constructor for *T, like Provide, after checking that it
+// returns exactly (*T, error). When the constructor fails, the resolution fails with its error
+// wrapped with the name of *T.
+//
+// Example:
+//
+//	goinject.ProvideResult[DB](container, func(cfg *Config) (*DB, error) {
+//	    return Open(cfg.DSN)
+//	})
+//	db, err := goinject.Get[DB](container) // "*main.DB: connection refused"
+func ProvideResult[T any](c *Container, ctor any) error {
+
+	want := reflect.TypeFor[*T]()
+
+	ctorType := reflect.TypeOf(ctor)
+	{
+		if ctorType == nil || ctorType.Kind() != reflect.Func {
+			return fmt.Errorf("%w, got %s", ErrFactoryMustBeAFunction, c.name(ctorType))
+		}
+
+		if ctorType.NumOut() != 2 || ctorType.Out(0) != want || ctorType.Out(1) != errorType {
+			return fmt.Errorf("%w, want (%s, error), got %s", ErrConstructorMustReturnValue, c.name(want), c.name(ctorType))
+		}
+	}
+
+	ctorValue := reflect.ValueOf(ctor)
+
+	// Only the errors of the constructor itself are wrapped, dependencies name themselves.
+	wrapped := reflect.MakeFunc(ctorType, func(args []reflect.Value) []reflect.Value {
+
+		var out []reflect.Value
+		if ctorType.IsVariadic() {
+			out = ctorValue.CallSlice(args)
+		} else {
+			out = ctorValue.Call(args)
+		}
+
+		if !out[1].IsNil() {
+			err := fmt.Errorf("%s: %w", c.name(want), out[1].Interface().(error))
+			out[1] = reflect.ValueOf(&err).Elem()
+		}
+
+		return out
+	})
+
+	return c.Provide(wrapped.Interface())
+}
+
+// parseConstructor validates a constructor and wraps it into a factory entry
+// whose arguments are resolved from the container resolving it.
+// It returns the type the constructor produces.
+func (c *Container) parseConstructor(ctor any) (typeof, *factory, error) {
+
+	ctorValue := reflect.ValueOf(ctor)
+
+	ctorType := ctorValue.Type()
+	{
+		if ctorType.Kind() != reflect.Func {
+			return nil, nil, fmt.Errorf("%w, got %s", ErrFactoryMustBeAFunction, c.name(ctorType))
+		}
+
+		if n := ctorType.NumOut(); n == 0 || n > 2 || n == 2 && ctorType.Out(1) != errorType {
+			return nil, nil, fmt.Errorf("%w, got %s", ErrConstructorMustReturnValue, c.name(ctorType))
+		}
+	}
+
+	typeof := ctorType.Out(0)
+
+	if typeof.Kind() != reflect.Ptr {
+		return nil, nil, fmt.Errorf("%w, got %s", ErrOutputMustBeAPointer, c.name(ctorType))
+	}
+
+	deps := make([]reflect.Type, ctorType.NumIn())
+	for i := range deps {
+		deps[i] = ctorType.In(i)
+	}
+
+	variadic := ctorType.IsVariadic()
+
+	// Instances that know their consumer cannot be shared between consumers.
+	perRequester := slices.Contains(deps, typeType)
+
+	factory := newFactory(typeof, func(ctx context.Context, c *Container) (any, error) {
+
+		args, err := c.arguments(ctx, typeof, deps, variadic)
+		{
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var out []reflect.Value
+		if variadic {
+			out = ctorValue.CallSlice(args)
+		} else {
+			out = ctorValue.Call(args)
+		}
+
+		if len(out) == 2 && !out[1].IsNil() {
+			return nil, out[1].Interface().(error)
+		}
+
+		return out[0].Interface(), nil
+	})
+	factory.deps = deps
+	factory.variadic = variadic
+
+	if perRequester {
+		factory.lifetime = Transient
+	}
+
+	return typeof, factory, nil
+}
+
+// arguments resolves the parameters of a constructor for the given type.
+func (c *Container) arguments(ctx context.Context, typeof typeof, deps []typeof, variadic bool) ([]reflect.Value, error) {
+
+	args := make([]reflect.Value, len(deps))
+
+	requester := typeof
+
+	if r, ok := ctx.(*requested); ok {
+		ctx, requester = r.Context, r.by
+	}
+
+	// The dependencies learn which type requests them.
+	depCtx := &requested{ctx, typeof}
+
+	for i, dep := range deps {
+		if dep == contextType {
+			args[i] = reflect.ValueOf(&ctx).Elem()
+			continue
+		}
+
+		if dep == typeType {
+			args[i] = reflect.ValueOf(&requester).Elem()
+			continue
+		}
+
+		// The container is not a registration, resolving it would look for itself.
+		if dep == containerType {
+			args[i] = reflect.ValueOf(c)
+			continue
+		}
+
+		if variadic && i == len(deps)-1 {
+			services, err := c.collect(depCtx, dep.Elem())
+			{
+				if err != nil {
+					return nil, fmt.Errorf("dependency %s of %s: %w", c.name(dep), c.name(typeof), err)
+				}
+			}
+
+			args[i] = reflect.MakeSlice(dep, 0, len(services))
+			for _, service := range services {
+				args[i] = reflect.Append(args[i], reflect.ValueOf(service))
+			}
+
+			continue
+		}
+
+		service, err := c.resolve(depCtx, dep)
+		{
+			if err != nil && c.degrade(dep, typeof, err) {
+				args[i] = reflect.Zero(dep)
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("dependency %s of %s: %w", c.name(dep), c.name(typeof), err)
+			}
+		}
+
+		args[i] = reflect.ValueOf(service)
+	}
+
+	// A dependency may have cancelled the resolution, the constructor must not run then.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// degrade reports whether a dependency that failed to resolve is passed as nil,
+// see WithGracefulMissing, and reports it if so. Only a dependency that has no registration
+// itself degrades; a failure further down its own dependencies is returned unchanged.
+func (c *Container) degrade(dep, typeof typeof, err error) bool {
+
+	if !c.graceful || dep.Kind() != reflect.Ptr || !absent(err, dep) {
+		return false
+	}
+
+	if c.logger != nil {
+		c.logger("warn", "missing dependency passed as nil", "type", c.name(dep), "of", c.name(typeof))
+	}
+
+	if c.onGraceful != nil {
+		c.onGraceful(dep, typeof)
+	}
+
+	return true
+}
+
+// ProvideAll registers many constructors at once.
+// Every valid constructor is registered; the errors of the invalid ones are
+// joined and reported with their index.
+//
+// Example:
+//
+//	err := container.ProvideAll(NewConfig, NewDB, NewUserRepository)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Container) ProvideAll(ctors ...any) error {
+
+	var errs []error
+
+	for i, ctor := range ctors {
+		if err := c.Provide(ctor); err != nil {
+			errs = append(errs, fmt.Errorf("constructor %d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
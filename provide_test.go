@@ -0,0 +1,381 @@
+package goinject
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type (
+	TestRepository struct {
+		Service *TestService
+	}
+
+	TestHandler struct {
+		Repository *TestRepository
+	}
+)
+
+func TestContainer_Provide(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func(s *TestService) *TestRepository {
+		return &TestRepository{Service: s}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	result, err := Get[TestRepository](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if result.Service != service {
+		t.Errorf("Provide() injected = %p, want %p", result.Service, service)
+	}
+}
+
+func TestContainer_ProvideError(t *testing.T) {
+	errBroken := errors.New("broken")
+	c := New()
+
+	if err := c.Provide(func() (*TestService, error) {
+		return nil, errBroken
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, errBroken) {
+		t.Errorf("Get[T]() error = %v, want %v", err, errBroken)
+	}
+}
+
+func TestContainer_ProvideMissingDependency(t *testing.T) {
+	c := New()
+
+	if err := c.Provide(func(s *TestService) *TestRepository {
+		return &TestRepository{Service: s}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if _, err := Get[TestRepository](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
+
+func TestContainer_ProvideAll(t *testing.T) {
+	c := New()
+
+	err := c.ProvideAll(
+		func() *TestService { return &TestService{Name: "test"} },
+		func() TestRepository { return TestRepository{} },
+		func(r *TestRepository) *TestHandler { return &TestHandler{Repository: r} },
+	)
+
+	if !errors.Is(err, ErrOutputMustBeAPointer) {
+		t.Fatalf("ProvideAll() error = %v, want %v", err, ErrOutputMustBeAPointer)
+	}
+
+	if !strings.Contains(err.Error(), "constructor 1") {
+		t.Errorf("ProvideAll() error = %q, want it to name constructor 1", err)
+	}
+
+	if _, err := Get[TestService](c); err != nil {
+		t.Errorf("Get[TestService]() unexpected error = %v", err)
+	}
+
+	if got := len(c.RegisteredTypesOrdered()); got != 2 {
+		t.Errorf("ProvideAll() registered %d constructors, want %d", got, 2)
+	}
+}
+
+type (
+	TestPlugin interface {
+		PluginName() string
+	}
+
+	TestPluginA struct{}
+	TestPluginB struct{}
+
+	TestPluginHost struct {
+		Plugins []TestPlugin
+	}
+)
+
+func (*TestPluginA) PluginName() string { return "a" }
+func (*TestPluginB) PluginName() string { return "b" }
+
+func newTestPluginHost(plugins ...TestPlugin) *TestPluginHost {
+	return &TestPluginHost{Plugins: plugins}
+}
+
+func TestContainer_ProvideVariadic(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestPluginB{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Register(&TestService{Name: "not a plugin"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Register(&TestPluginA{}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(newTestPluginHost); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	host, err := Get[TestPluginHost](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	var names []string
+	for _, plugin := range host.Plugins {
+		names = append(names, plugin.PluginName())
+	}
+
+	if want := []string{"b", "a"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Provide() injected plugins = %v, want %v", names, want)
+	}
+}
+
+func TestContainer_ProvideVariadicEmpty(t *testing.T) {
+	c := New()
+
+	if err := c.Provide(newTestPluginHost); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	host, err := Get[TestPluginHost](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if len(host.Plugins) != 0 {
+		t.Errorf("Provide() injected plugins = %v, want none", host.Plugins)
+	}
+}
+
+func TestMustProvide(t *testing.T) {
+	c := New()
+
+	chained := MustProvide(MustProvide(c, func() *TestService {
+		return &TestService{Name: "test"}
+	}), func(s *TestService) *TestRepository {
+		return &TestRepository{Service: s}
+	})
+
+	if chained != c {
+		t.Errorf("MustProvide() got = %p, want %p", chained, c)
+	}
+
+	if _, err := Get[TestRepository](c); err != nil {
+		t.Errorf("Get[T]() unexpected error = %v", err)
+	}
+
+	// Test panic on an invalid constructor
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, ErrOutputMustBeAPointer) {
+			t.Errorf("MustProvide() panic = %v, want %v", err, ErrOutputMustBeAPointer)
+		}
+	}()
+
+	MustProvide(c, func() TestService { return TestService{} })
+}
+
+func TestContainer_Provide_Container(t *testing.T) {
+	c := New()
+
+	if err := c.Register(&TestService{Name: "test"}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.Provide(func(container *Container) (*TestRepository, error) {
+		service, err := Get[TestService](container)
+		if err != nil {
+			return nil, err
+		}
+
+		return &TestRepository{Service: service}, nil
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if _, err := c.Plan(&TestRepository{}); err != nil {
+		t.Errorf("Plan() unexpected error = %v", err)
+	}
+
+	repository, err := Get[TestRepository](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if repository.Service.Name != "test" {
+		t.Errorf("Get[T]() got = %v, want %v", repository.Service.Name, "test")
+	}
+}
+
+func TestContainer_Provide_CompositeInterface(t *testing.T) {
+	newService := func(rw io.ReadWriter) *TestService {
+		return &TestService{Name: fmt.Sprintf("%T", rw)}
+	}
+
+	t.Run("implemented by one registration", func(t *testing.T) {
+		c := New(WithStructuralResolution())
+
+		if err := c.Register(&bytes.Buffer{}); err != nil {
+			t.Fatalf("failed to register buffer: %v", err)
+		}
+
+		if err := c.Provide(newService); err != nil {
+			t.Fatalf("Provide() unexpected error = %v", err)
+		}
+
+		service, err := Get[TestService](c)
+		if err != nil || service.Name != "*bytes.Buffer" {
+			t.Errorf("Get[T]() got = %v, %v, want the buffer", service, err)
+		}
+	})
+
+	t.Run("implemented in parts", func(t *testing.T) {
+		c := New(WithStructuralResolution())
+
+		if err := c.Register(strings.NewReader("")); err != nil {
+			t.Fatalf("failed to register reader: %v", err)
+		}
+
+		if err := c.Register(bufio.NewWriter(io.Discard)); err != nil {
+			t.Fatalf("failed to register writer: %v", err)
+		}
+
+		if err := c.Provide(newService); err != nil {
+			t.Fatalf("Provide() unexpected error = %v", err)
+		}
+
+		if _, err := Get[TestService](c); !errors.Is(err, ErrPartialImplementation) {
+			t.Errorf("Get[T]() error = %v, want %v", err, ErrPartialImplementation)
+		}
+
+		if _, err := c.Plan(&TestService{}); !errors.Is(err, ErrPartialImplementation) {
+			t.Errorf("Plan() error = %v, want %v", err, ErrPartialImplementation)
+		}
+	})
+}
+
+func TestContainer_ProvideRequestedBy(t *testing.T) {
+	type (
+		Logger     struct{ Owner reflect.Type }
+		Repository struct{ Log *Logger }
+		Handler    struct {
+			Repository *Repository
+			Log        *Logger
+		}
+	)
+
+	c := New()
+
+	if err := c.ProvideAll(
+		func(requestedBy reflect.Type) *Logger { return &Logger{Owner: requestedBy} },
+		func(log *Logger) *Repository { return &Repository{Log: log} },
+		func(r *Repository, log *Logger) *Handler { return &Handler{Repository: r, Log: log} },
+	); err != nil {
+		t.Fatalf("ProvideAll() unexpected error = %v", err)
+	}
+
+	if plan, err := c.Plan(&Handler{}); err != nil || len(plan) != 3 {
+		t.Errorf("Plan() got = %v, %v, want the logger, the repository and the handler", plan, err)
+	}
+
+	handler := MustGet[Handler](c)
+
+	if got, want := handler.Log.Owner, reflect.TypeFor[*Handler](); got != want {
+		t.Errorf("logger of the handler requested by %v, want %v", got, want)
+	}
+
+	if got, want := handler.Repository.Log.Owner, reflect.TypeFor[*Repository](); got != want {
+		t.Errorf("logger of the repository requested by %v, want %v", got, want)
+	}
+
+	if got, want := MustGet[Logger](c).Owner, reflect.TypeFor[*Logger](); got != want {
+		t.Errorf("top-level logger requested by %v, want %v", got, want)
+	}
+}
+
+func TestProvideResult(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{"succeeds", nil, nil},
+		{"fails", errUnavailable, errUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+
+			if err := c.Register(&TestService{Name: "db"}); err != nil {
+				t.Fatalf("failed to register service: %v", err)
+			}
+
+			if err := ProvideResult[TestRepository](c, func(s *TestService) (*TestRepository, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &TestRepository{Service: s}, nil
+			}); err != nil {
+				t.Fatalf("ProvideResult() unexpected error = %v", err)
+			}
+
+			repository, err := Get[TestRepository](c)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get[T]() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && repository.Service.Name != "db" {
+				t.Errorf("Get[T]() got = %v, want a repository of the db", repository)
+			}
+
+			if tt.wantErr != nil && !strings.HasPrefix(err.Error(), "*goinject.TestRepository: ") {
+				t.Errorf("Get[T]() error = %v, want it wrapped with the type", err)
+			}
+		})
+	}
+}
+
+func TestProvideResult_Signature(t *testing.T) {
+	c := New()
+
+	for _, ctor := range []any{
+		func() *TestRepository { return nil },
+		func() (*TestService, error) { return nil, nil },
+		func() (*TestRepository, bool) { return nil, false },
+	} {
+		if err := ProvideResult[TestRepository](c, ctor); !errors.Is(err, ErrConstructorMustReturnValue) {
+			t.Errorf("ProvideResult(%T) error = %v, want %v", ctor, err, ErrConstructorMustReturnValue)
+		}
+	}
+
+	if err := ProvideResult[TestRepository](c, &TestRepository{}); !errors.Is(err, ErrFactoryMustBeAFunction) {
+		t.Errorf("ProvideResult() error = %v, want %v", err, ErrFactoryMustBeAFunction)
+	}
+}
@@ -0,0 +1,65 @@
+package goinject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Provider is a custom resolution strategy for a single type, for construction logic that a
+// constructor cannot express, such as reading the value from the environment or a remote config.
+// Type returns the type the provider is registered under, a pointer or an interface type, and
+// Provide builds a value of that type, resolving what it needs from the container it is given.
+type Provider interface {
+	Provide(c *Container) (any, error)
+	Type() reflect.Type
+}
+
+// RegisterProvider registers p under p.Type(). The container calls p.Provide on every
+// resolution, so caching is up to the provider; its instances are, like transient ones,
+// not tracked by the container and not disposed on Close.
+//
+// Example:
+//
+//	type envConfig struct{}
+//
+//	func (envConfig) Type() reflect.Type { return reflect.TypeFor[*Config]() }
+//
+//	func (envConfig) Provide(*goinject.Container) (any, error) {
+//	    return &Config{DSN: os.Getenv("DSN")}, nil
+//	}
+//
+//	container.RegisterProvider(envConfig{})
+func (c *Container) RegisterProvider(p Provider) error {
+
+	if p == nil {
+		return fmt.Errorf("%w, got nil provider", ErrFactoryMustBeAFunction)
+	}
+
+	typeof := p.Type()
+	{
+		if typeof == nil || typeof.Kind() != reflect.Ptr && typeof.Kind() != reflect.Interface {
+			return fmt.Errorf("%w, provider %s has type %s", ErrOutputMustBeAPointer, c.nameOf(p), c.name(typeof))
+		}
+	}
+
+	f := newFactory(typeof, func(_ context.Context, c *Container) (any, error) {
+
+		service, err := p.Provide(c)
+		{
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if service == nil || !reflect.TypeOf(service).AssignableTo(typeof) {
+			return nil, fmt.Errorf("provider %s for %s returned %s: %w", c.nameOf(p), c.name(typeof), c.nameOf(service), ErrDoesNotImplement)
+		}
+
+		return service, nil
+	})
+
+	f.lifetime = Transient
+
+	return c.addFactory(typeof, f)
+}
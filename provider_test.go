@@ -0,0 +1,113 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type (
+	// TestEnv is the source TestEnvProvider reads from.
+	TestEnv map[string]string
+
+	// TestEnvProvider builds a TestService named after the SERVICE_NAME entry of TestEnv.
+	TestEnvProvider struct {
+		calls int
+	}
+
+	// TestBrokenProvider returns a value of the wrong type.
+	TestBrokenProvider struct{}
+)
+
+func (p *TestEnvProvider) Type() reflect.Type {
+	return reflect.TypeFor[*TestService]()
+}
+
+func (p *TestEnvProvider) Provide(c *Container) (any, error) {
+
+	env, err := Get[TestEnv](c)
+	if err != nil {
+		return nil, err
+	}
+
+	p.calls++
+
+	return &TestService{Name: (*env)["SERVICE_NAME"]}, nil
+}
+
+func (TestBrokenProvider) Type() reflect.Type {
+	return reflect.TypeFor[TestReader]()
+}
+
+func (TestBrokenProvider) Provide(*Container) (any, error) {
+	return &TestService{}, nil
+}
+
+func TestContainer_RegisterProvider(t *testing.T) {
+	c := New()
+	provider := &TestEnvProvider{}
+
+	if err := c.RegisterProvider(provider); err != nil {
+		t.Fatalf("RegisterProvider() unexpected error = %v", err)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Get[TestService]() without a source error = %v, want %v", err, ErrServiceNotFound)
+	}
+
+	env := TestEnv{"SERVICE_NAME": "from-env"}
+	if err := c.Register(&env); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	for range 2 {
+		service, err := Get[TestService](c)
+		if err != nil || service.Name != "from-env" {
+			t.Fatalf("Get[TestService]() got = %v, %v, want %q", service, err, "from-env")
+		}
+	}
+
+	if provider.calls != 2 {
+		t.Errorf("Provide() called %d times, want once per resolution", provider.calls)
+	}
+}
+
+func TestContainer_RegisterProvider_Invalid(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterProvider(nil); !errors.Is(err, ErrFactoryMustBeAFunction) {
+		t.Errorf("RegisterProvider(nil) error = %v, want %v", err, ErrFactoryMustBeAFunction)
+	}
+
+	if err := c.RegisterProvider(TestBrokenProvider{}); err != nil {
+		t.Fatalf("RegisterProvider() unexpected error = %v", err)
+	}
+
+	var reader TestReader
+	if _, err := c.Get(&reader); !errors.Is(err, ErrDoesNotImplement) {
+		t.Errorf("Get(TestReader) error = %v, want %v", err, ErrDoesNotImplement)
+	}
+}
+
+// TestSelfProvider resolves the type it provides.
+type TestSelfProvider struct{}
+
+func (TestSelfProvider) Type() reflect.Type {
+	return reflect.TypeFor[*TestService]()
+}
+
+func (TestSelfProvider) Provide(c *Container) (any, error) {
+	return Get[TestService](c)
+}
+
+func TestContainer_RegisterProvider_Cycle(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterProvider(TestSelfProvider{}); err != nil {
+		t.Fatalf("RegisterProvider() unexpected error = %v", err)
+	}
+
+	if _, err := Get[TestService](c); !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Get[TestService]() error = %v, want %v", err, ErrCircularDependency)
+	}
+}
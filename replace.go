@@ -0,0 +1,77 @@
+package goinject
+
+import (
+	"reflect"
+)
+
+// RegisterOrReplace registers a singleton instance like Register and returns the instance it
+// replaces: the previously registered one, or the one built by a factory for the same type.
+// It returns nil when nothing was resolvable for the type yet. Interfaces bound to the type
+// follow its current registration, so they resolve to the new instance from then on.
+//
+// Example:
+//
+//	previous, err := container.RegisterOrReplace(&Config{Env: "prod"})
+func (c *Container) RegisterOrReplace(service any) (any, error) {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.replaceProvider(typeof, service)
+}
+
+// Replace atomically swaps the registered *T for instance and returns the previous
+// instance, or nil if there was none, so that the caller can dispose it.
+//
+// Example:
+//
+//	previous, err := goinject.Replace(container, &Config{Env: "prod"})
+//	if err == nil && previous != nil {
+//	    previous.Close()
+//	}
+func Replace[T any](c *Container, instance *T) (*T, error) {
+
+	previous, err := c.replaceProvider(reflect.TypeFor[*T](), instance)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p, _ := previous.(*T)
+
+	return p, nil
+}
+
+// replaceProvider stores the instance under the type and returns the instance it replaces.
+func (c *Container) replaceProvider(typeof typeof, service any) (any, error) {
+
+	service, err := c.registering(typeof, service)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+
+	previous, ok := c.providers[typeof]
+	if !ok {
+		if factory := c.factories[typeof]; factory != nil {
+			previous, _ = factory.cached()
+		}
+	}
+
+	c.putProvider(typeof, service)
+
+	return previous, nil
+}
@@ -0,0 +1,95 @@
+package goinject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	c := New()
+	first := &TestService{Name: "first"}
+	second := &TestService{Name: "second"}
+
+	previous, err := Replace(c, first)
+	if err != nil || previous != nil {
+		t.Errorf("Replace() got = %v, %v, want nil", previous, err)
+	}
+
+	previous, err = Replace(c, second)
+	if err != nil || previous != first {
+		t.Errorf("Replace() got = %v, %v, want %v", previous, err, first)
+	}
+
+	result, err := Get[TestService](c)
+	if err != nil || result != second {
+		t.Errorf("Get[T]() got = %v, %v, want %v", result, err, second)
+	}
+
+	c.Freeze()
+
+	if _, err := Replace(c, first); !errors.Is(err, ErrContainerFrozen) {
+		t.Errorf("Replace() error = %v, want %v", err, ErrContainerFrozen)
+	}
+}
+
+func TestContainer_RegisterOrReplace(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "built"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	built, err := Get[TestService](c)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	replacement := &TestService{Name: "replacement"}
+
+	previous, err := c.RegisterOrReplace(replacement)
+	if err != nil || previous != built {
+		t.Errorf("RegisterOrReplace() got = %v, %v, want %v", previous, err, built)
+	}
+
+	if result, err := Get[TestService](c); err != nil || result != replacement {
+		t.Errorf("Get[T]() got = %v, %v, want %v", result, err, replacement)
+	}
+}
+
+func TestContainer_RegisterOrReplaceFollowedByBindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(c *Container) error
+	}{
+		{"bound instance", func(c *Container) error { return RegisterAs[TestReader](c, &TestStore{data: "stale"}) }},
+		{"bound factory", func(c *Container) error {
+			return Bind[TestReader](c, func() *TestStore { return &TestStore{data: "stale"} })
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+
+			if err := tt.register(c); err != nil {
+				t.Fatalf("failed to bind TestReader: %v", err)
+			}
+
+			var reader TestReader
+			if _, err := c.Get(&reader); err != nil || reader.Read() != "stale" {
+				t.Fatalf("Get(TestReader) got = %v, %v, want the stale store", reader, err)
+			}
+
+			replacement := &TestStore{data: "fresh"}
+			if _, err := c.RegisterOrReplace(replacement); err != nil {
+				t.Fatalf("RegisterOrReplace() unexpected error = %v", err)
+			}
+
+			if _, err := c.Get(&reader); err != nil || reader != TestReader(replacement) {
+				t.Errorf("Get(TestReader) after replacement got = %v, %v, want %v", reader, err, replacement)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package goinject
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Scope creates a child container configured with the same options as c.
+// Resolution in the scope falls back to the registrations of c: singletons are built
+// and cached in c, while scoped factories are built with the scope's dependencies and
+// cached in the scope, so every scope gets its own instance. Registrations made
+// in the scope are only visible to the scope. Closing the scope disposes only the
+// instances it holds, its own registrations and scoped instances, never the singletons of c.
+//
+// Example:
+//
+//	container.RegisterScoped(NewRequestContext)
+//
+//	request := container.Scope()
+//	ctx, _ := goinject.Get[RequestContext](request)
+func (c *Container) Scope() *Container {
+
+	scope := New(c.opts...)
+	scope.parent = c
+
+	return scope
+}
+
+// ScopeWith creates a child scope like Scope and registers the override instances in it,
+// shadowing the registrations of c for the same types within the scope only. Scoped factories
+// resolved in the scope receive the overrides, while singletons of c keep their dependencies.
+// It panics with a *MustPanic, like MustRegister, if an override cannot be registered.
+//
+// Example:
+//
+//	request := container.ScopeWith(&FakeClock{}, &RequestUser{ID: 42})
+func (c *Container) ScopeWith(overrides ...any) *Container {
+
+	scope := c.Scope()
+
+	for _, override := range overrides {
+		MustRegister(scope, override)
+	}
+
+	return scope
+}
+
+// RegisterScoped registers a constructor, like Provide, whose instance is shared within
+// each scope resolving it instead of across the whole container. See Scope.
+//
+// Example:
+//
+//	container.RegisterScoped(func(db *DB) *UnitOfWork {
+//	    return db.Begin()
+//	})
+func (c *Container) RegisterScoped(ctor any) error {
+
+	typeof, f, err := c.parseConstructor(ctor)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	f.lifetime = Scoped
+
+	return c.addFactory(typeof, f)
+}
+
+// With returns an independent copy of the container in which the override instances replace
+// the registrations for their types, leaving c untouched. Unlike Scope, the copy does not fall
+// back to c: it starts open with the same registrations and options, shares the registered
+// instances, and builds its own instances from the factories, with the overrides as dependencies.
+// Closing the copy disposes only the overrides and the instances it built, not those shared with c.
+// It panics with a *MustPanic, like MustRegister, if an override cannot be registered.
+//
+// Example:
+//
+//	base := goinject.New()
+//	base.Register(&Config{Env: "prod"})
+//	base.Provide(NewServer)
+//
+//	staging := base.With(&Config{Env: "staging"})
+func (c *Container) With(overrides ...any) *Container {
+
+	clone := New(c.opts...)
+	clone.parent = c.parent
+
+	c.mu.RLock()
+
+	maps.Copy(clone.providers, c.providers)
+	maps.Copy(clone.bindings, c.bindings)
+	maps.Copy(clone.finalizers, c.finalizers)
+	maps.Copy(clone.hints, c.hints)
+	maps.Copy(clone.priorities, c.priorities)
+	maps.Copy(clone.named, c.named)
+
+	for typeof, f := range c.factories {
+		clone.factories[typeof] = f.fresh()
+	}
+
+	for key, f := range c.namedCtors {
+		clone.namedCtors[key] = f.fresh()
+	}
+
+	for typeof, f := range c.namedFactories {
+		clone.namedFactories[typeof] = &namedFactory{call: f.call}
+	}
+
+	for typeof, f := range c.keyed {
+		clone.keyed[typeof] = f.(interface{ fresh() any }).fresh()
+	}
+
+	for group, members := range c.groups {
+		clone.groups[group] = slices.Clone(members)
+	}
+
+	for group, members := range c.typedGroups {
+		clone.typedGroups[group] = slices.Clone(members)
+	}
+
+	for tag, types := range c.tags {
+		clone.tags[tag] = slices.Clone(types)
+	}
+
+	clone.order = slices.Clone(c.order)
+	clone.built = slices.Clone(c.built)
+
+	// The shared instances belong to c, the copy never disposes them.
+	c.mu.RUnlock()
+
+	for _, override := range overrides {
+		typeof, service, err := clone.provider(override)
+		{
+			if err == nil {
+				service, err = clone.registering(typeof, service)
+			}
+
+			if err != nil {
+				panic(c.mustPanic("With", reflect.TypeOf(override), err))
+			}
+		}
+
+		// The type is still registered while the instance goes in, so it keeps its place in the order.
+		clone.putProvider(typeof, service)
+		delete(clone.factories, typeof)
+	}
+
+	return clone
+}
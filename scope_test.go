@@ -0,0 +1,346 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestContainer_Scope(t *testing.T) {
+	c := New()
+	singletons, scoped := 0, 0
+
+	if err := c.Provide(func() *TestService {
+		singletons++
+		return &TestService{Name: "singleton"}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterScoped(func(service *TestService) *TestRepository {
+		scoped++
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("RegisterScoped() unexpected error = %v", err)
+	}
+
+	first, second := c.Scope(), c.Scope()
+
+	a, err := Get[TestRepository](first)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	b, err := Get[TestRepository](second)
+	if err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	if a == b {
+		t.Errorf("Get[T]() in two scopes got the same scoped instance %p", a)
+	}
+
+	if a.Service != b.Service {
+		t.Errorf("scoped instances got singletons %p and %p, want the same", a.Service, b.Service)
+	}
+
+	if again, _ := Get[TestRepository](first); again != a {
+		t.Errorf("Get[T]() in the same scope got = %p, want %p", again, a)
+	}
+
+	if singletons != 1 || scoped != 2 {
+		t.Errorf("built %d singletons and %d scoped instances, want 1 and 2", singletons, scoped)
+	}
+
+	// The singleton is cached in the parent, the scoped instances are not.
+	if got := c.MaterializedTypes(); len(got) != 1 {
+		t.Errorf("MaterializedTypes() of the parent got = %v, want only the singleton", got)
+	}
+}
+
+func TestContainer_Scope_Close(t *testing.T) {
+	c := New()
+
+	type (
+		Shared  struct{ TestDisposable }
+		Request struct{ TestDisposable }
+		Local   struct{ TestDisposable }
+	)
+
+	var disposed []string
+
+	if err := c.Register(&TestDisposable{Name: "registered", disposed: &disposed}); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func() *Shared {
+		return &Shared{TestDisposable{Name: "shared", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterScoped(func(*Shared) *Request {
+		return &Request{TestDisposable{Name: "request", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("RegisterScoped() unexpected error = %v", err)
+	}
+
+	scope := c.ScopeWith(&Local{TestDisposable{Name: "local", disposed: &disposed}})
+
+	MustGet[TestDisposable](scope)
+	MustGet[Local](scope)
+	request := MustGet[Request](scope)
+
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Close() of the scope unexpected error = %v", err)
+	}
+
+	if want := []string{"request", "local"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("Close() of the scope disposed %v, want only the scope-local %v", disposed, want)
+	}
+
+	// The parent singletons survive and stay resolvable.
+	if _, err := Get[Shared](c); err != nil {
+		t.Errorf("Get[Shared]() after closing the scope unexpected error = %v", err)
+	}
+
+	if other := MustGet[Request](c.Scope()); other == request {
+		t.Error("a new scope got the instance of the closed one")
+	}
+
+	disposed = nil
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() unexpected error = %v", err)
+	}
+
+	if want := []string{"shared", "registered"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("Close() of the parent disposed %v, want %v", disposed, want)
+	}
+}
+
+func TestContainer_Scope_Container(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterScoped(func(scope *Container) *AnotherService {
+		scope.Register(&TestService{Name: "local"})
+		return &AnotherService{ID: 1}
+	}); err != nil {
+		t.Fatalf("RegisterScoped() unexpected error = %v", err)
+	}
+
+	scope := c.Scope()
+
+	if _, err := Get[AnotherService](scope); err != nil {
+		t.Fatalf("Get[T]() unexpected error = %v", err)
+	}
+
+	// The constructor received the scope, not the container it is registered in.
+	if _, err := Get[TestService](scope); err != nil {
+		t.Errorf("Get[T]() in the scope unexpected error = %v", err)
+	}
+
+	if _, err := Get[TestService](c); err == nil {
+		t.Error("Get[T]() in the parent expected an error for a scope registration")
+	}
+}
+
+func TestContainer_ScopeWith(t *testing.T) {
+	c := New()
+	actual := &TestService{Name: "real"}
+
+	if err := c.Register(actual); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.RegisterScoped(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("RegisterScoped() unexpected error = %v", err)
+	}
+
+	mock := &TestService{Name: "mock"}
+	scope := c.ScopeWith(mock)
+
+	if got := MustGet[TestService](scope); got != mock {
+		t.Errorf("Get[T]() in the scope got = %v, want the mock", got.Name)
+	}
+
+	if got := MustGet[TestRepository](scope); got.Service != mock {
+		t.Errorf("scoped instance got = %v, want the mock", got.Service.Name)
+	}
+
+	if got := MustGet[TestService](c); got != actual {
+		t.Errorf("Get[T]() in the parent got = %v, want the actual service", got.Name)
+	}
+
+	if got := MustGet[TestRepository](c.Scope()); got.Service != actual {
+		t.Errorf("scoped instance of another scope got = %v, want the actual service", got.Service.Name)
+	}
+}
+
+func TestContainer_With(t *testing.T) {
+	base := New()
+	original := &TestService{Name: "original"}
+
+	if err := base.Register(original); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := base.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	baseRepository := MustGet[TestRepository](base)
+
+	override := &TestService{Name: "override"}
+	derived := base.With(override)
+
+	if got := MustGet[TestService](derived); got != override {
+		t.Errorf("Get[T]() in the derived container got = %v, want the override", got.Name)
+	}
+
+	if got := MustGet[TestRepository](derived); got == baseRepository || got.Service != override {
+		t.Errorf("derived repository got = %p of %v, want its own repository of the override", got, got.Service.Name)
+	}
+
+	if got := MustGet[TestService](base); got != original {
+		t.Errorf("Get[T]() in the base got = %v, want the original", got.Name)
+	}
+
+	if got := MustGet[TestRepository](base); got != baseRepository {
+		t.Errorf("Get[T]() in the base got = %p, want the original repository %p", got, baseRepository)
+	}
+
+	if err := derived.Register(&AnotherService{}); err != nil {
+		t.Fatalf("failed to register in the derived container: %v", err)
+	}
+
+	if _, err := Get[AnotherService](base); err == nil {
+		t.Error("registrations in the derived container should not reach the base")
+	}
+}
+
+func TestContainer_With_Close(t *testing.T) {
+	base := New()
+
+	type (
+		Built    struct{ TestDisposable }
+		Override struct{ TestDisposable }
+	)
+
+	var disposed []string
+
+	if err := base.Register(&TestDisposable{Name: "shared", disposed: &disposed}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := base.Register(&Override{TestDisposable{Name: "replaced", disposed: &disposed}}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := base.Provide(func(*TestDisposable) *Built {
+		return &Built{TestDisposable{Name: "built", disposed: &disposed}}
+	}); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	derived := base.With(&Override{TestDisposable{Name: "override", disposed: &disposed}})
+	MustGet[Built](derived)
+
+	if err := derived.Close(); err != nil {
+		t.Fatalf("Close() of the derived container unexpected error = %v", err)
+	}
+
+	if want := []string{"built", "override"}; !reflect.DeepEqual(disposed, want) {
+		t.Errorf("Close() of the derived container disposed %v, want only its own %v", disposed, want)
+	}
+
+	if state := base.State(); state != Open {
+		t.Errorf("State() of the base got = %v, want %v", state, Open)
+	}
+
+	if _, err := Get[TestDisposable](base); err != nil {
+		t.Errorf("Get[TestDisposable]() in the base unexpected error = %v", err)
+	}
+}
+
+// TestScopeFlusher resolves services from its scope while it is disposed.
+type TestScopeFlusher struct {
+	scope    *Container
+	flushed  *TestService
+	notBuilt error
+}
+
+func (f *TestScopeFlusher) Dispose() error {
+
+	service, err := Get[TestService](f.scope)
+	if err != nil {
+		return err
+	}
+
+	f.flushed = service
+	_, f.notBuilt = Get[AnotherService](f.scope)
+
+	return nil
+}
+
+func TestContainer_Scope_CloseReachesParent(t *testing.T) {
+	c := New()
+	shared := &TestService{Name: "shared"}
+
+	if err := c.Register(shared); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(func() *AnotherService {
+		return &AnotherService{}
+	}); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	scope := c.Scope()
+	flusher := &TestScopeFlusher{scope: scope}
+
+	if err := scope.Register(flusher); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Close() of the scope unexpected error = %v", err)
+	}
+
+	if flusher.flushed != shared {
+		t.Errorf("Dispose() resolved %p from the closing scope, want the parent singleton %p", flusher.flushed, shared)
+	}
+
+	if !errors.Is(flusher.notBuilt, ErrContainerClosed) {
+		t.Errorf("Dispose() resolving an unbuilt parent service error = %v, want %v", flusher.notBuilt, ErrContainerClosed)
+	}
+
+	if _, err := Get[TestService](scope); !errors.Is(err, ErrContainerClosed) {
+		t.Errorf("Get[T]() after the scope is closed error = %v, want %v", err, ErrContainerClosed)
+	}
+}
+
+func TestContainer_With_OverrideFactory(t *testing.T) {
+	base := New()
+
+	if err := base.Provide(func() *TestService { return &TestService{Name: "built"} }); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	override := &TestService{Name: "override"}
+	derived := base.With(override)
+
+	if got := derived.RegisteredTypesOrdered(); len(got) != 1 {
+		t.Errorf("RegisteredTypesOrdered() got = %v, want the overridden type once", got)
+	}
+
+	services, err := GetSlice[*TestService](derived)
+	if err != nil || len(services) != 1 || services[0] != override {
+		t.Errorf("GetSlice[T]() got = %v, %v, want only the override", services, err)
+	}
+}
@@ -0,0 +1,275 @@
+package goinject
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// snapshotMagic starts every metadata snapshot, followed by the format version.
+const (
+	snapshotMagic   = "GIM"
+	snapshotVersion = 1
+)
+
+// Kinds of entries in a metadata snapshot.
+const (
+	snapshotFactory byte = iota
+	snapshotBinding
+)
+
+// SnapshotMeta encodes the shape of the container, its factory registrations and interface
+// bindings in registration order, into a compact binary form that LoadMeta rebuilds a container from.
+// Registered instances, and the bindings to them, are runtime state and are not part of the
+// snapshot. A factory keyed under an interface cannot be described by name and makes
+// SnapshotMeta fail.
+//
+// Example:
+//
+//	data, err := container.SnapshotMeta()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("wiring.bin", data, 0o644)
+func (c *Container) SnapshotMeta() ([]byte, error) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data := append([]byte(snapshotMagic), snapshotVersion)
+
+	var entries [][]byte
+
+	for _, typeof := range c.order {
+		if target := c.bindings[typeof]; target != nil {
+			// A binding to an instance goes with the instance.
+			if c.factories[target] == nil {
+				continue
+			}
+
+			entry := []byte{snapshotBinding}
+			entry = appendString(entry, c.name(typeof))
+			entry = appendString(entry, c.name(target))
+			entries = append(entries, entry)
+			continue
+		}
+
+		factory := c.factories[typeof]
+		if factory == nil {
+			continue
+		}
+
+		if typeof.Kind() == reflect.Interface {
+			return nil, fmt.Errorf("factory for %s is keyed under an interface and cannot be snapshotted", c.name(typeof))
+		}
+
+		entry := []byte{snapshotFactory, byte(factory.lifetime)}
+		entry = appendString(entry, c.name(typeof))
+		entries = append(entries, entry)
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(entries)))
+	for _, entry := range entries {
+		data = append(data, entry...)
+	}
+
+	return data, nil
+}
+
+// LoadMeta builds a container with the shape recorded by SnapshotMeta.
+// Factories are looked up by the name of the type they are registered under, rendered
+// with the WithTypeNameFunc formatter among opts if the snapshot was taken with one, and are
+// registered with the lifetime they had: like Provide for singletons, and like RegisterCached,
+// RegisterTransient, RegisterScoped or RegisterGoroutineLocal otherwise. A factory that cannot
+// be registered with its recorded lifetime makes LoadMeta fail.
+// Interfaces of bindings are given as a nil pointer to the interface under its name.
+//
+// Example:
+//
+//	container, err := goinject.LoadMeta(data, map[string]any{
+//	    "*main.DB":        NewDB,
+//	    "*main.UserStore": NewUserStore,
+//	    "main.Store":      (*Store)(nil),
+//	}, goinject.WithLogger(logger))
+func LoadMeta(data []byte, factories map[string]any, opts ...Option) (*Container, error) {
+
+	r := bytes.NewReader(data)
+
+	header := make([]byte, len(snapshotMagic)+1)
+	{
+		if _, err := io.ReadFull(r, header); err != nil || string(header[:len(snapshotMagic)]) != snapshotMagic {
+			return nil, fmt.Errorf("%w: missing header", ErrInvalidSnapshot)
+		}
+
+		if version := header[len(snapshotMagic)]; version != snapshotVersion {
+			return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, version)
+		}
+	}
+
+	n, err := binary.ReadUvarint(r)
+	{
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+	}
+
+	c := New(opts...)
+	types := make(map[string]reflect.Type)
+
+	for range n {
+		kind, err := r.ReadByte()
+		{
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+			}
+		}
+
+		switch kind {
+		case snapshotFactory:
+			if err := loadFactory(c, r, factories, types); err != nil {
+				return nil, err
+			}
+		case snapshotBinding:
+			if err := loadBinding(c, r, factories, types); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown entry kind %d", ErrInvalidSnapshot, kind)
+		}
+	}
+
+	return c, nil
+}
+
+// loadFactory registers the factory entry read from r.
+func loadFactory(c *Container, r *bytes.Reader, factories map[string]any, types map[string]reflect.Type) error {
+
+	lifetime, err := r.ReadByte()
+	{
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+	}
+
+	name, err := readString(r)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	factory, ok := factories[name]
+	{
+		if !ok || factory == nil {
+			return fmt.Errorf("no factory supplied for %s", name)
+		}
+	}
+
+	typeof := reflect.TypeOf(factory)
+	{
+		if typeof.Kind() != reflect.Func || typeof.NumOut() == 0 || c.name(typeof.Out(0)) != name {
+			return fmt.Errorf("factory supplied for %s builds %s", name, c.name(typeof))
+		}
+	}
+
+	switch Lifetime(lifetime) {
+	case Singleton:
+		err = c.Provide(factory)
+	case Cached:
+		err = c.RegisterCached(factory)
+	case Transient:
+		// A constructor taking the requesting type is transient through Provide.
+		if typeof.NumIn() == 0 {
+			err = c.RegisterTransient(factory)
+		} else {
+			err = c.Provide(factory)
+		}
+	case Scoped:
+		err = c.RegisterScoped(factory)
+	case GoroutineLocal:
+		err = c.RegisterGoroutineLocal(factory)
+	default:
+		return fmt.Errorf("%w: unknown lifetime %d of %s", ErrInvalidSnapshot, lifetime, name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	c.mu.RLock()
+	registered := c.factories[typeof.Out(0)].lifetime
+	c.mu.RUnlock()
+
+	if registered != Lifetime(lifetime) {
+		return fmt.Errorf("factory supplied for %s is %s, the snapshot records %s", name, registered, Lifetime(lifetime))
+	}
+
+	types[name] = typeof.Out(0)
+
+	return nil
+}
+
+// loadBinding binds the interface entry read from r to its concrete registration.
+func loadBinding(c *Container, r *bytes.Reader, factories map[string]any, types map[string]reflect.Type) error {
+
+	name, err := readString(r)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	target, err := readString(r)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	iface, err := keyOf(factories[name])
+	{
+		if err != nil || iface.Kind() != reflect.Interface {
+			return fmt.Errorf("no interface supplied for %s", name)
+		}
+	}
+
+	concrete, ok := types[target]
+	{
+		if !ok {
+			return fmt.Errorf("binding %s targets %s, which is not in the snapshot", name, target)
+		}
+	}
+
+	if err := c.implements(concrete, iface); err != nil {
+		return err
+	}
+
+	return c.addBinding(iface, concrete)
+}
+
+// appendString appends a length-prefixed string.
+func appendString(data []byte, s string) []byte {
+	return append(binary.AppendUvarint(data, uint64(len(s))), s...)
+}
+
+// readString reads a length-prefixed string.
+func readString(r *bytes.Reader) (string, error) {
+
+	n, err := binary.ReadUvarint(r)
+	{
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+
+		if n > uint64(r.Len()) {
+			return "", fmt.Errorf("%w: string of %d bytes exceeds the data", ErrInvalidSnapshot, n)
+		}
+	}
+
+	s := make([]byte, n)
+	_, _ = r.Read(s)
+
+	return string(s), nil
+}
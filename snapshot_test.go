@@ -0,0 +1,151 @@
+package goinject
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainer_SnapshotMeta(t *testing.T) {
+	newService := func() *TestService { return &TestService{Name: "test"} }
+	newRepository := func(service *TestService) *TestRepository { return &TestRepository{Service: service} }
+	newStore := func() *TestStore { return &TestStore{} }
+
+	c := New()
+
+	if err := c.Provide(newService); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Provide(newRepository); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	if err := Bind[TestReader](c, newStore); err != nil {
+		t.Fatalf("failed to bind store: %v", err)
+	}
+
+	if err := c.RegisterCached(func() *AnotherService { return &AnotherService{ID: 1} }); err != nil {
+		t.Fatalf("failed to register cached factory: %v", err)
+	}
+
+	// Instances are not part of the snapshot.
+	if err := c.Register(&TestHandler{}); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+
+	data, err := c.SnapshotMeta()
+	if err != nil {
+		t.Fatalf("SnapshotMeta() unexpected error = %v", err)
+	}
+
+	factories := map[string]any{
+		"*goinject.TestService":    newService,
+		"*goinject.TestRepository": newRepository,
+		"*goinject.TestStore":      newStore,
+		"*goinject.AnotherService": func() *AnotherService { return &AnotherService{ID: 2} },
+		"goinject.TestReader":      (*TestReader)(nil),
+	}
+
+	loaded, err := LoadMeta(data, factories)
+	if err != nil {
+		t.Fatalf("LoadMeta() unexpected error = %v", err)
+	}
+
+	want := []reflect.Type{
+		reflect.TypeOf(&TestService{}),
+		reflect.TypeOf(&TestRepository{}),
+		reflect.TypeOf(&TestStore{}),
+		reflect.TypeFor[TestReader](),
+		reflect.TypeOf(&AnotherService{}),
+	}
+	if got := loaded.RegisteredTypesOrdered(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RegisteredTypesOrdered() got = %v, want %v", got, want)
+	}
+
+	repository, err := Get[TestRepository](loaded)
+	if err != nil || repository.Service.Name != "test" {
+		t.Errorf("Get[TestRepository]() got = %v, %v", repository, err)
+	}
+
+	if _, err := Get[TestReader](loaded); err != nil {
+		t.Errorf("Get[TestReader]() unexpected error = %v", err)
+	}
+
+	if _, err := LoadMeta(data, map[string]any{}); err == nil {
+		t.Error("LoadMeta() expected an error for a missing factory")
+	}
+
+	if _, err := LoadMeta(data, map[string]any{"*goinject.TestService": nil}); err == nil || !strings.Contains(err.Error(), "no factory supplied") {
+		t.Errorf("LoadMeta() error = %v, want the nil factory reported", err)
+	}
+
+	if _, err := LoadMeta(data[:len(data)-3], factories); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("LoadMeta() error = %v, want %v", err, ErrInvalidSnapshot)
+	}
+}
+
+func TestContainer_SnapshotMetaLifetimes(t *testing.T) {
+	newService := func() *TestService { return &TestService{} }
+	newRepository := func(*TestService) *TestRepository { return &TestRepository{} }
+	newStore := func() *TestStore { return &TestStore{} }
+	newHandler := func(reflect.Type) *TestHandler { return &TestHandler{} }
+
+	c := New()
+
+	if err := c.RegisterTransient(newService); err != nil {
+		t.Fatalf("RegisterTransient() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterScoped(newRepository); err != nil {
+		t.Fatalf("RegisterScoped() unexpected error = %v", err)
+	}
+
+	if err := c.RegisterGoroutineLocal(newStore); err != nil {
+		t.Fatalf("RegisterGoroutineLocal() unexpected error = %v", err)
+	}
+
+	if err := c.Provide(newHandler); err != nil {
+		t.Fatalf("Provide() unexpected error = %v", err)
+	}
+
+	data, err := c.SnapshotMeta()
+	if err != nil {
+		t.Fatalf("SnapshotMeta() unexpected error = %v", err)
+	}
+
+	factories := map[string]any{
+		"*goinject.TestService":    newService,
+		"*goinject.TestRepository": newRepository,
+		"*goinject.TestStore":      newStore,
+		"*goinject.TestHandler":    newHandler,
+	}
+
+	loaded, err := LoadMeta(data, factories)
+	if err != nil {
+		t.Fatalf("LoadMeta() unexpected error = %v", err)
+	}
+
+	for typeof, want := range map[reflect.Type]Lifetime{
+		reflect.TypeFor[*TestService]():    Transient,
+		reflect.TypeFor[*TestRepository](): Scoped,
+		reflect.TypeFor[*TestStore]():      GoroutineLocal,
+		reflect.TypeFor[*TestHandler]():    Transient,
+	} {
+		if got := loaded.factories[typeof].lifetime; got != want {
+			t.Errorf("lifetime of %s after LoadMeta() got = %v, want %v", typeof, got, want)
+		}
+	}
+
+	if MustGet[TestService](loaded) == MustGet[TestService](loaded) {
+		t.Error("Get[TestService]() after LoadMeta() returned the same instance twice, want a transient")
+	}
+
+	// A factory that cannot have the recorded lifetime.
+	factories["*goinject.TestService"] = func(*TestStore) *TestService { return &TestService{} }
+
+	if _, err := LoadMeta(data, factories); err == nil || !strings.Contains(err.Error(), "transient") {
+		t.Errorf("LoadMeta() error = %v, want the lifetime mismatch reported", err)
+	}
+}
@@ -0,0 +1,169 @@
+package goinject
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// buildBuckets are the upper bounds of the factory duration histogram.
+var buildBuckets = [...]time.Duration{
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// TypeStats are the resolution statistics of one type.
+type TypeStats struct {
+	Type        reflect.Type
+	Resolutions uint64
+	Builds      uint64
+	BuildTime   time.Duration
+}
+
+// metrics collects resolution statistics per type without locking the resolution path.
+type metrics struct {
+	types sync.Map // typeof -> *typeMetrics
+}
+
+// typeMetrics are the counters of one type.
+type typeMetrics struct {
+	resolutions atomic.Uint64
+	builds      atomic.Uint64
+	buildTime   atomic.Int64
+	buckets     [len(buildBuckets) + 1]atomic.Uint64
+}
+
+// of returns the counters of the type, creating them on first use.
+func (m *metrics) of(typeof typeof) *typeMetrics {
+
+	if t, ok := m.types.Load(typeof); ok {
+		return t.(*typeMetrics)
+	}
+
+	t, _ := m.types.LoadOrStore(typeof, &typeMetrics{})
+
+	return t.(*typeMetrics)
+}
+
+// measured reports whether factory calls are counted: for Stats, and for telling built from
+// cached instances to the resolve metric and the resolve events.
+func (c *Container) measured() bool {
+	return c.stats || c.onResolve != nil || c.events.active.Load()
+}
+
+// observe records a factory call of the given duration.
+func (t *typeMetrics) observe(d time.Duration) {
+
+	t.builds.Add(1)
+	t.buildTime.Add(int64(d))
+
+	i, _ := slices.BinarySearch(buildBuckets[:], d)
+	t.buckets[i].Add(1)
+}
+
+// Stats returns the resolution statistics of every type resolved so far, sorted by type name.
+// Resolutions counts every resolution of the type, including as a dependency; Builds and
+// BuildTime count the factory calls and their total duration. The statistics are only
+// collected by a container created with WithStats; Stats returns nil otherwise.
+//
+// Example:
+//
+//	container := goinject.New(goinject.WithStats())
+//
+//	for _, s := range container.Stats() {
+//	    fmt.Printf("%s resolved %d times, built in %s\n", s.Type, s.Resolutions, s.BuildTime)
+//	}
+func (c *Container) Stats() []TypeStats {
+
+	if !c.stats {
+		return nil
+	}
+
+	var stats []TypeStats
+
+	c.metrics.types.Range(func(key, value any) bool {
+		t := value.(*typeMetrics)
+
+		stats = append(stats, TypeStats{
+			Type:        key.(reflect.Type),
+			Resolutions: t.resolutions.Load(),
+			Builds:      t.builds.Load(),
+			BuildTime:   time.Duration(t.buildTime.Load()),
+		})
+
+		return true
+	})
+
+	slices.SortFunc(stats, func(a, b TypeStats) int {
+		return cmp.Compare(c.name(a.Type), c.name(b.Type))
+	})
+
+	return stats
+}
+
+// WritePrometheus writes the statistics in the Prometheus text exposition format:
+// the goinject_resolutions_total counter and the goinject_factory_duration_seconds
+// histogram, both labeled with the type name. It writes no samples unless the container
+// was created with WithStats.
+//
+// Example:
+//
+//	http.HandleFunc("/metrics/di", func(w http.ResponseWriter, r *http.Request) {
+//	    container.WritePrometheus(w)
+//	})
+func (c *Container) WritePrometheus(w io.Writer) error {
+
+	var b strings.Builder
+
+	stats := c.Stats()
+
+	b.WriteString("# HELP goinject_resolutions_total Number of resolutions by type.\n")
+	b.WriteString("# TYPE goinject_resolutions_total counter\n")
+
+	for _, s := range stats {
+		fmt.Fprintf(&b, "goinject_resolutions_total{type=\"%s\"} %d\n", labelValue(c.name(s.Type)), s.Resolutions)
+	}
+
+	b.WriteString("# HELP goinject_factory_duration_seconds Duration of factory calls by type.\n")
+	b.WriteString("# TYPE goinject_factory_duration_seconds histogram\n")
+
+	for _, s := range stats {
+		if s.Builds == 0 {
+			continue
+		}
+
+		label := labelValue(c.name(s.Type))
+		t := c.metrics.of(s.Type)
+
+		var count uint64
+		for i, bound := range buildBuckets {
+			count += t.buckets[i].Load()
+			fmt.Fprintf(&b, "goinject_factory_duration_seconds_bucket{type=\"%s\",le=\"%g\"} %d\n", label, bound.Seconds(), count)
+		}
+
+		count += t.buckets[len(buildBuckets)].Load()
+
+		fmt.Fprintf(&b, "goinject_factory_duration_seconds_bucket{type=\"%s\",le=\"+Inf\"} %d\n", label, count)
+		fmt.Fprintf(&b, "goinject_factory_duration_seconds_sum{type=\"%s\"} %g\n", label, s.BuildTime.Seconds())
+		fmt.Fprintf(&b, "goinject_factory_duration_seconds_count{type=\"%s\"} %d\n", label, count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// labelValue escapes a Prometheus label value.
+func labelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,85 @@
+package goinject
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainer_Stats(t *testing.T) {
+	c := New(WithStats())
+
+	if err := c.Provide(func() *TestService {
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to provide service: %v", err)
+	}
+
+	if err := c.Provide(func(service *TestService) *TestRepository {
+		return &TestRepository{Service: service}
+	}); err != nil {
+		t.Fatalf("failed to provide repository: %v", err)
+	}
+
+	for range 3 {
+		if _, err := Get[TestRepository](c); err != nil {
+			t.Fatalf("Get[T]() unexpected error = %v", err)
+		}
+	}
+
+	stats := c.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("Stats() got = %v, want two types", stats)
+	}
+
+	repository, service := stats[0], stats[1]
+
+	if repository.Type != reflect.TypeOf(&TestRepository{}) || repository.Resolutions != 3 || repository.Builds != 1 {
+		t.Errorf("Stats() got = %+v for the repository", repository)
+	}
+
+	if service.Type != reflect.TypeOf(&TestService{}) || service.Resolutions != 1 || service.Builds != 1 {
+		t.Errorf("Stats() got = %+v for the service", service)
+	}
+
+	if stats := New().Stats(); stats != nil {
+		t.Errorf("Stats() got = %v without WithStats, want nil", stats)
+	}
+}
+
+func TestContainer_WritePrometheus(t *testing.T) {
+	c := New(WithTypeNameFunc(func(typeof reflect.Type) string {
+		return `odd "name"` + "\n" + typeof.String()
+	}), WithStats())
+
+	if err := c.RegisterFactory(func() *TestService {
+		return &TestService{Name: "test"}
+	}); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	for range 2 {
+		if _, err := Get[TestService](c); err != nil {
+			t.Fatalf("Get[T]() unexpected error = %v", err)
+		}
+	}
+
+	var b strings.Builder
+	if err := c.WritePrometheus(&b); err != nil {
+		t.Fatalf("WritePrometheus() unexpected error = %v", err)
+	}
+
+	label := `type="odd \"name\"\n*goinject.TestService"`
+
+	for _, want := range []string{
+		"# TYPE goinject_resolutions_total counter\n",
+		"goinject_resolutions_total{" + label + "} 2\n",
+		"# TYPE goinject_factory_duration_seconds histogram\n",
+		"goinject_factory_duration_seconds_bucket{" + label + `,le="+Inf"} 1` + "\n",
+		"goinject_factory_duration_seconds_count{" + label + "} 1\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WritePrometheus() output is missing %q in:\n%s", want, b.String())
+		}
+	}
+}
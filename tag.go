@@ -0,0 +1,68 @@
+package goinject
+
+import (
+	"context"
+	"slices"
+)
+
+// RegisterTagged registers a singleton instance and attaches the given tags to its type,
+// so that services can be selected by tag independently of their type with GetByTag.
+//
+// Example:
+//
+//	container.RegisterTagged(&UserCache{}, "cacheable", "users")
+//	container.RegisterTagged(&ReportCache{}, "cacheable")
+func (c *Container) RegisterTagged(service any, tags ...string) error {
+
+	typeof, service, err := c.provider(service)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.addProvider(typeof, service); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		if !slices.Contains(c.tags[tag], typeof) {
+			c.tags[tag] = append(c.tags[tag], typeof)
+		}
+	}
+
+	return nil
+}
+
+// GetByTag resolves every service carrying the tag, in tagging order.
+// Services that cannot be resolved anymore are left out.
+//
+// Example:
+//
+//	for _, service := range container.GetByTag("cacheable") {
+//	    service.(Cache).Purge()
+//	}
+func (c *Container) GetByTag(tag string) []any {
+
+	c.mu.RLock()
+	types := slices.Clone(c.tags[tag])
+	c.mu.RUnlock()
+
+	services := make([]any, 0, len(types))
+
+	for _, typeof := range types {
+		service, err := c.resolve(context.Background(), typeof)
+		{
+			if err != nil {
+				continue
+			}
+		}
+
+		services = append(services, service)
+	}
+
+	return services
+}
@@ -0,0 +1,53 @@
+package goinject
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContainer_GetByTag(t *testing.T) {
+	service := &TestService{Name: "test"}
+	repository := &TestRepository{}
+	handler := &TestHandler{}
+
+	c := New()
+
+	for _, r := range []struct {
+		service any
+		tags    []string
+	}{
+		{service, []string{"cacheable", "core"}},
+		{repository, []string{"cacheable"}},
+		{handler, []string{"core", "http"}},
+	} {
+		if err := c.RegisterTagged(r.service, r.tags...); err != nil {
+			t.Fatalf("RegisterTagged() unexpected error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		tag  string
+		want []any
+	}{
+		{"cacheable", []any{service, repository}},
+		{"core", []any{service, handler}},
+		{"http", []any{handler}},
+		{"unknown", []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := c.GetByTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetByTag(%q) got = %v, want %v", tt.tag, got, tt.want)
+			}
+		})
+	}
+
+	if !c.Unregister(&TestService{}) {
+		t.Fatal("Unregister() should remove the service")
+	}
+
+	if got := c.GetByTag("core"); !reflect.DeepEqual(got, []any{handler}) {
+		t.Errorf("GetByTag() after Unregister got = %v, want %v", got, []any{handler})
+	}
+}
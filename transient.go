@@ -0,0 +1,57 @@
+package goinject
+
+import (
+	"context"
+)
+
+// RegisterTransient registers a factory that builds a new instance on every resolution.
+// Transient instances are not tracked by the container and are not disposed on Close.
+//
+// Example:
+//
+//	container.RegisterTransient(func() *RequestContext {
+//	    return &RequestContext{}
+//	})
+func (c *Container) RegisterTransient(factory any) error {
+
+	typeof, f, err := c.parseFactory(factory)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	f.lifetime = Transient
+
+	return c.addFactory(typeof, f)
+}
+
+// Pin resolves the type of out once and registers the result as a singleton instance,
+// so that every later resolution returns the pinned instance, even for a transient.
+//
+// Example:
+//
+//	pinned, err := container.Pin(&RequestContext{})
+func (c *Container) Pin(out any) (any, error) {
+
+	typeof, err := keyOf(out)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := c.resolve(context.Background(), typeof)
+	{
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The instance already went through the registration hooks when it was registered or built.
+	if err := c.setProvider(typeof, service); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
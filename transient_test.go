@@ -0,0 +1,39 @@
+package goinject
+
+import (
+	"testing"
+)
+
+func TestContainer_Pin(t *testing.T) {
+	c := New()
+	builds := 0
+
+	if err := c.RegisterTransient(func() *TestService {
+		builds++
+		return &TestService{Name: "transient"}
+	}); err != nil {
+		t.Fatalf("RegisterTransient() unexpected error = %v", err)
+	}
+
+	first, _ := Get[TestService](c)
+	second, _ := Get[TestService](c)
+
+	if first == second {
+		t.Errorf("Get[T]() of a transient got the same instance %p twice", first)
+	}
+
+	pinned, err := c.Pin(&TestService{})
+	if err != nil {
+		t.Fatalf("Pin() unexpected error = %v", err)
+	}
+
+	for range 2 {
+		if result, err := Get[TestService](c); err != nil || result != pinned {
+			t.Errorf("Get[T]() after Pin got = %p, %v, want %p", result, err, pinned)
+		}
+	}
+
+	if builds != 3 {
+		t.Errorf("factory ran %d times, want %d", builds, 3)
+	}
+}
@@ -1,5 +1,15 @@
 package goinject
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
 // Get retrieves a dependency of type T from the container.
 // It returns a pointer to the dependency and an error if not found.
 //
@@ -38,9 +48,7 @@ package goinject
 //	fmt.Println(userService.Name) // Prints: John
 func Get[T any](c *Container) (*T, error) {
 
-	var out T
-
-	v, err := c.Get(&out)
+	v, err := c.resolve(context.Background(), keyFor[T]())
 	{
 		if err != nil {
 			return nil, err
@@ -50,6 +58,11 @@ func Get[T any](c *Container) (*T, error) {
 	o, ok := v.(*T)
 	{
 		if !ok {
+			// Interface services resolve to the concrete instance.
+			if iface, ok := v.(T); ok {
+				return &iface, nil
+			}
+
 			return nil, ErrOutputMustBeAPointer
 		}
 	}
@@ -57,19 +70,115 @@ func Get[T any](c *Container) (*T, error) {
 	return o, nil
 }
 
+// token is a zero-size key standing for T in the keys cache.
+type token[T any] struct{}
+
+// keys caches the registration key of every T resolved through the generic helpers,
+// so that repeated calls neither allocate an out value nor reflect on it.
+var keys sync.Map
+
+// keyFor returns the type T is registered under, like keyOf for a *T out pointer.
+func keyFor[T any]() typeof {
+
+	if key, ok := keys.Load(token[T]{}); ok {
+		return key.(reflect.Type)
+	}
+
+	typeof, _ := keyOf((*T)(nil))
+	keys.Store(token[T]{}, typeof)
+
+	return typeof
+}
+
+// GetOk retrieves a dependency of type T and separates absence from failure.
+// The bool is false only when T is not registered, in which case the error is nil;
+// the error is non-nil only when T is registered but could not be resolved.
+//
+// Example:
+//
+//	cache, ok, err := goinject.GetOk[Cache](container)
+//	if err != nil {
+//	    log.Fatal(err) // registered but broken
+//	}
+//	if !ok {
+//	    cache = NewMemoryCache() // simply absent
+//	}
+func GetOk[T any](c *Container) (*T, bool, error) {
+
+	v, err := Get[T](c)
+	{
+		// A miss on T itself is reported with the bare sentinel, or as unbound under
+		// WithStrictInterfaces, while a miss further down the graph is wrapped with
+		// the dependency chain.
+		if absent(err, keyFor[T]()) {
+			return nil, false, nil
+		}
+
+		if err != nil {
+			return nil, true, err
+		}
+	}
+
+	return v, true, nil
+}
+
+// GetOrNil retrieves a dependency of type T, or returns nil if T is not registered.
+// Unlike Get, absence is not an error; it panics with a *MustPanic, like MustGet,
+// when T is registered but cannot be resolved, for example when its factory fails.
+//
+// Example:
+//
+//	if tracer := goinject.GetOrNil[Tracer](container); tracer != nil {
+//	    tracer.Start()
+//	}
+func GetOrNil[T any](c *Container) *T {
+	return getOrNil[T](c, "GetOrNil")
+}
+
+// GetPtrOrNil is GetOrNil under another name: GetOrNil already returns the shared
+// instance of T, never a copy.
+//
+// Deprecated: use GetOrNil.
+func GetPtrOrNil[T any](c *Container) *T {
+	return getOrNil[T](c, "GetPtrOrNil")
+}
+
+// getOrNil resolves T like GetOk, returning nil when T is not registered
+// and panicking with a *MustPanic for op when it cannot be resolved.
+func getOrNil[T any](c *Container, op string) *T {
+
+	v, _, err := GetOk[T](c)
+	{
+		if err != nil {
+			panic(c.mustPanic(op, reflect.TypeFor[*T](), err))
+		}
+	}
+
+	return v
+}
+
 // GetValue retrieves a dependency and copies its value into the provided pointer.
-// It returns an error if the dependency is not found.
+// It returns an error if the dependency is not found; the error names T and
+// still matches ErrServiceNotFound with errors.Is.
 //
 // Example:
 //
 //	var user UserService
 //	err := goinject.GetValue(container, &user)
 //	if err != nil {
-//	    log.Fatal(err)
+//	    log.Fatal(err) // GetValue[main.UserService]: service not found
 //	}
 //	fmt.Println(user.Name) // Prints: John
 func GetValue[T any](c *Container, out *T) error {
-	return c.GetValue(out)
+
+	err := c.GetValue(out)
+	{
+		if errors.Is(err, ErrServiceNotFound) {
+			return fmt.Errorf("GetValue[%s]: %w", c.name(reflect.TypeFor[T]()), err)
+		}
+	}
+
+	return err
 }
 
 // MustGet retrieves a dependency of type T from the container.
@@ -84,9 +193,145 @@ func MustGet[T any](c *Container) *T {
 	v, err := Get[T](c)
 	{
 		if err != nil {
-			panic(err)
+			panic(c.mustPanic("MustGet", reflect.TypeFor[*T](), err))
 		}
 	}
 
 	return v
 }
+
+// MustPanic is the value every Must helper panics with, so that a single recovery
+// handler can report the operation, the type involved and the underlying error.
+// It is an error that unwraps to Err.
+//
+// Example:
+//
+//	defer func() {
+//	    if p, ok := recover().(*goinject.MustPanic); ok {
+//	        log.Fatalf("%s %s: %v", p.Op, p.Type, p.Err)
+//	    }
+//	}()
+type MustPanic struct {
+	Op   string
+	Type reflect.Type
+	Err  error
+
+	name string // Type rendered by the container, see WithTypeNameFunc.
+}
+
+// mustPanic returns the panic value of a failed Must helper.
+func (c *Container) mustPanic(op string, typeof typeof, err error) *MustPanic {
+	return &MustPanic{Op: op, Type: typeof, Err: err, name: c.name(typeof)}
+}
+
+// Error returns the operation, the type and the underlying error.
+func (p *MustPanic) Error() string {
+
+	if p.name != "" {
+		return fmt.Sprintf("%s %s: %v", p.Op, p.name, p.Err)
+	}
+
+	return fmt.Sprintf("%s %s: %v", p.Op, p.Type, p.Err)
+}
+
+// Unwrap returns the underlying error.
+func (p *MustPanic) Unwrap() error {
+	return p.Err
+}
+
+// MustRegister registers a singleton instance and returns the container for chaining.
+// It panics with a *MustPanic if the instance cannot be registered.
+//
+// Example:
+//
+//	goinject.MustRegister(container, &Config{Env: "prod"})
+func MustRegister(c *Container, service any) *Container {
+
+	if err := c.Register(service); err != nil {
+		panic(c.mustPanic("MustRegister", reflect.TypeOf(service), err))
+	}
+
+	return c
+}
+
+// MustInvoke calls fn with its arguments resolved from the container, like Invoke.
+// It panics with a *MustPanic if an argument cannot be resolved or fn returns an error.
+//
+// Example:
+//
+//	goinject.MustInvoke(container, func(db *DB) error {
+//	    return db.Migrate()
+//	})
+func MustInvoke(c *Container, fn any) {
+	if err := c.Invoke(fn); err != nil {
+		panic(c.mustPanic("MustInvoke", reflect.TypeOf(fn), err))
+	}
+}
+
+// MustProvide registers a constructor and returns the container for chaining.
+// It panics with a *MustPanic wrapping the registration error if the constructor is invalid.
+//
+// Example:
+//
+//	goinject.MustProvide(goinject.MustProvide(container, NewDB), NewUserRepository)
+func MustProvide(c *Container, ctor any) *Container {
+
+	if err := c.Provide(ctor); err != nil {
+		panic(c.mustPanic("MustProvide", reflect.TypeOf(ctor), err))
+	}
+
+	return c
+}
+
+// PickE resolves a dependency of type T and passes it to fn.
+// It returns an error without calling fn if the dependency is not found.
+//
+// Example:
+//
+//	err := goinject.PickE(container, func(userService *UserService) {
+//	    fmt.Println(userService.Name) // Prints: John
+//	})
+func PickE[T any](c *Container, fn func(*T)) error {
+
+	v, err := Get[T](c)
+	{
+		if err != nil {
+			return err
+		}
+	}
+
+	fn(v)
+
+	return nil
+}
+
+// Pick resolves a dependency of type T and passes it to fn.
+// It panics if the dependency is not found.
+//
+// Example:
+//
+//	goinject.Pick(container, func(userService *UserService) {
+//	    fmt.Println(userService.Name) // Prints: John
+//	})
+func Pick[T any](c *Container, fn func(*T)) {
+	fn(MustGet[T](c))
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from its stack header.
+// It detects reentrant resolution and keys goroutine-local instances, and is never exposed.
+func goroutineID() uint64 {
+
+	var buf [64]byte
+
+	// The header looks like "goroutine 42 [running]:".
+	header := buf[len("goroutine "):runtime.Stack(buf[:], false)]
+
+	end := 0
+	for end < len(header) && header[end] >= '0' && header[end] <= '9' {
+		end++
+	}
+
+	id, _ := strconv.ParseUint(string(header[:end]), 10, 64)
+
+	return id
+}
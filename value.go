@@ -0,0 +1,44 @@
+package goinject
+
+import (
+	"context"
+	"reflect"
+)
+
+// RegisterFunc registers a function that lazily computes a value of type T.
+// The function runs once, on first resolution, and its result is cached like a singleton.
+// The value is retrieved with GetCopy, or through a pointer with Get.
+//
+// Example:
+//
+//	goinject.RegisterFunc(container, func() time.Duration {
+//	    return loadTimeout()
+//	})
+//	timeout, _ := goinject.GetCopy[time.Duration](container)
+func RegisterFunc[T any](c *Container, f func() T) error {
+
+	typeof := reflect.TypeFor[*T]()
+
+	return c.addFactory(typeof, newFactory(typeof, func(context.Context, *Container) (any, error) {
+		value := f()
+		return &value, nil
+	}))
+}
+
+// GetCopy retrieves a dependency of type T and returns a copy of its value.
+//
+// Example:
+//
+//	timeout, err := goinject.GetCopy[time.Duration](container)
+func GetCopy[T any](c *Container) (T, error) {
+
+	v, err := Get[T](c)
+	{
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
+	return *v, nil
+}
@@ -0,0 +1,42 @@
+package goinject
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	c := New()
+	var calls atomic.Int32
+
+	if err := RegisterFunc(c, func() time.Duration {
+		calls.Add(1)
+		return 5 * time.Second
+	}); err != nil {
+		t.Fatalf("RegisterFunc() unexpected error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if got, err := GetCopy[time.Duration](c); err != nil || got != 5*time.Second {
+				t.Errorf("GetCopy[T]() got = %v, %v, want %v", got, err, 5*time.Second)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("function ran %d times, want 1", got)
+	}
+
+	if _, err := GetCopy[int](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("GetCopy[T]() error = %v, want %v", err, ErrServiceNotFound)
+	}
+}
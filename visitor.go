@@ -0,0 +1,65 @@
+package goinject
+
+import (
+	"reflect"
+)
+
+// Visitor receives the registrations of a container, see Container.Accept.
+type Visitor interface {
+	// VisitProvider is called for a registered instance.
+	VisitProvider(t reflect.Type, instance any)
+	// VisitFactory is called for a factory or constructor registration.
+	VisitFactory(t reflect.Type, lifetime Lifetime)
+}
+
+// Accept calls the visitor for every registration, in registration order.
+// A type with a registered instance is visited as a provider even if it also has a factory.
+// An interface binding is visited under the interface, as a provider or a factory like the
+// registration it points to, in the place it was bound.
+// The visitor runs without holding the container lock and may call back into it.
+//
+// Example:
+//
+//	type printer struct{}
+//
+//	func (printer) VisitProvider(t reflect.Type, _ any) { fmt.Println("instance", t) }
+//	func (printer) VisitFactory(t reflect.Type, l goinject.Lifetime) { fmt.Println(l, t) }
+//
+//	container.Accept(printer{})
+func (c *Container) Accept(v Visitor) {
+
+	type visit struct {
+		typeof   typeof
+		instance any
+		provider bool
+		lifetime Lifetime
+	}
+
+	c.mu.RLock()
+
+	visits := make([]visit, 0, len(c.order))
+
+	for _, typeof := range c.order {
+		// Like a resolution, a binding is only followed for a type registered under nothing else.
+		target := typeof
+		if _, ok := c.providers[typeof]; !ok && c.factories[typeof] == nil && c.bindings[typeof] != nil {
+			target = c.bindings[typeof]
+		}
+
+		if instance, ok := c.providers[target]; ok {
+			visits = append(visits, visit{typeof: typeof, instance: instance, provider: true})
+		} else if factory := c.factories[target]; factory != nil {
+			visits = append(visits, visit{typeof: typeof, lifetime: factory.lifetime})
+		}
+	}
+
+	c.mu.RUnlock()
+
+	for _, visit := range visits {
+		if visit.provider {
+			v.VisitProvider(visit.typeof, visit.instance)
+		} else {
+			v.VisitFactory(visit.typeof, visit.lifetime)
+		}
+	}
+}
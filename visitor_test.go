@@ -0,0 +1,64 @@
+package goinject
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type recordingVisitor struct {
+	visits []string
+}
+
+func (v *recordingVisitor) VisitProvider(t reflect.Type, instance any) {
+	v.visits = append(v.visits, fmt.Sprintf("provider %s %p", t, instance))
+}
+
+func (v *recordingVisitor) VisitFactory(t reflect.Type, lifetime Lifetime) {
+	v.visits = append(v.visits, fmt.Sprintf("factory %s %s", t, lifetime))
+}
+
+func TestContainer_Accept(t *testing.T) {
+	c := New()
+	service := &TestService{Name: "test"}
+	store := &TestStore{}
+
+	if err := c.Register(service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	if err := c.RegisterFactory(func() *AnotherService { return &AnotherService{} }); err != nil {
+		t.Fatalf("failed to register factory: %v", err)
+	}
+
+	if err := c.Provide(func(s *TestService) *TestRepository { return &TestRepository{Service: s} }); err != nil {
+		t.Fatalf("failed to provide constructor: %v", err)
+	}
+
+	if err := RegisterAs[TestReader](c, store); err != nil {
+		t.Fatalf("failed to bind interface: %v", err)
+	}
+
+	if err := Bind[io.Writer](c, func() *bytes.Buffer { return &bytes.Buffer{} }); err != nil {
+		t.Fatalf("failed to bind factory: %v", err)
+	}
+
+	v := &recordingVisitor{}
+	c.Accept(v)
+
+	want := []string{
+		fmt.Sprintf("provider *goinject.TestService %p", service),
+		"factory *goinject.AnotherService singleton",
+		"factory *goinject.TestRepository singleton",
+		fmt.Sprintf("provider *goinject.TestStore %p", store),
+		fmt.Sprintf("provider goinject.TestReader %p", store),
+		"factory *bytes.Buffer singleton",
+		"factory io.Writer singleton",
+	}
+
+	if !reflect.DeepEqual(v.visits, want) {
+		t.Errorf("Accept() visits = %v, want %v", v.visits, want)
+	}
+}